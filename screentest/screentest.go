@@ -0,0 +1,261 @@
+// Package screentest drives termboxUtil controls without a terminal.
+// A Screen is an in-memory canvas of a fixed size that controls are
+// drawn to, events can be sent to controls and the result can be
+// checked cell by cell or against golden text.
+package screentest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/nsf/termbox-go"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a non-empty
+// value, makes AssertGolden write the current screen to the golden file
+// instead of comparing against it.
+const UpdateGoldenEnv = "TERMBOX_UPDATE_GOLDEN"
+
+// Control is the part of a termboxUtil control that the screen needs
+type Control interface {
+	GetX() int
+	GetY() int
+	GetWidth() int
+	GetHeight() int
+	HandleEvent(termbox.Event) bool
+	Draw()
+}
+
+// Screen is a fake terminal screen
+type Screen struct {
+	canvas *termboxUtil.BufferCanvas
+	fg, bg termbox.Attribute
+}
+
+// CreateScreen creates an empty screen that is w by h
+func CreateScreen(w, h int) *Screen {
+	s := Screen{canvas: termboxUtil.CreateBufferCanvas(w, h)}
+	return &s
+}
+
+// GetCanvas returns the canvas that the screen draws to
+func (s *Screen) GetCanvas() *termboxUtil.BufferCanvas { return s.canvas }
+
+// Size returns the width and height of the screen
+func (s *Screen) Size() (int, int) { return s.canvas.Size() }
+
+// Resize changes the size of the screen
+func (s *Screen) Resize(w, h int) { s.canvas.Resize(w, h) }
+
+// SetClearColors sets the colors the screen is cleared to before drawing
+func (s *Screen) SetClearColors(fg, bg termbox.Attribute) {
+	s.fg, s.bg = fg, bg
+}
+
+// Clear blanks the whole screen
+func (s *Screen) Clear() { s.canvas.Clear(' ', s.fg, s.bg) }
+
+// Draw clears the screen and draws the controls on it, in order
+func (s *Screen) Draw(ctls ...Control) {
+	s.Clear()
+	for _, c := range ctls {
+		termboxUtil.DrawToCanvas(s.canvas, c)
+	}
+}
+
+// Send passes the events to c one at a time and returns whether
+// each one was consumed. Resize events resize the screen before
+// they are passed on.
+func (s *Screen) Send(c Control, events ...termbox.Event) []bool {
+	ret := make([]bool, len(events))
+	for idx, ev := range events {
+		if ev.Type == termbox.EventResize {
+			s.Resize(ev.Width, ev.Height)
+		}
+		ret[idx] = c.HandleEvent(ev)
+	}
+	return ret
+}
+
+// Step sends the events to c and then redraws it
+func (s *Screen) Step(c Control, events ...termbox.Event) []bool {
+	ret := s.Send(c, events...)
+	s.Draw(c)
+	return ret
+}
+
+// Replay sends the events in rec to c without waiting between them,
+// to reproduce a session recorded with a termboxUtil.EventRecorder
+func (s *Screen) Replay(c Control, rec termboxUtil.Recording) []bool {
+	return s.Send(c, rec.Events()...)
+}
+
+// ReplayFile replays the recording in the file at path to c and then
+// redraws it. The test fails if the file can't be read.
+func (s *Screen) ReplayFile(t testing.TB, c Control, path string) []bool {
+	t.Helper()
+	rec, err := termboxUtil.LoadRecording(path)
+	if err != nil {
+		t.Fatalf("reading recording: %s", err)
+	}
+	ret := s.Replay(c, rec)
+	s.Draw(c)
+	return ret
+}
+
+// GetCell returns the cell at x, y
+func (s *Screen) GetCell(x, y int) termbox.Cell { return s.canvas.GetCell(x, y) }
+
+// GetStrings returns the whole screen as text
+func (s *Screen) GetStrings() []string { return s.canvas.GetStrings() }
+
+// GetRegion returns the text in the area at x, y that is w by h
+func (s *Screen) GetRegion(x, y, w, h int) []string {
+	ret := make([]string, 0, h)
+	for yy := y; yy < y+h; yy++ {
+		row := make([]rune, w)
+		for xx := range row {
+			row[xx] = s.canvas.GetCell(x+xx, yy).Ch
+			if row[xx] == 0 {
+				row[xx] = ' '
+			}
+		}
+		ret = append(ret, string(row))
+	}
+	return ret
+}
+
+// String returns the whole screen as text, one line per row
+func (s *Screen) String() string { return strings.Join(s.GetStrings(), "\n") }
+
+/* Assertions */
+
+// AssertRune fails the test if the cell at x, y doesn't contain ch
+func (s *Screen) AssertRune(t testing.TB, x, y int, ch rune) {
+	t.Helper()
+	got := s.GetCell(x, y).Ch
+	if got == 0 {
+		got = ' '
+	}
+	if got != ch {
+		t.Errorf("cell %d,%d: expected rune %q, got %q\n%s", x, y, ch, got, s)
+	}
+}
+
+// AssertCell fails the test if the cell at x, y doesn't have the rune ch
+// with foreground fg and background bg
+func (s *Screen) AssertCell(t testing.TB, x, y int, ch rune, fg, bg termbox.Attribute) {
+	t.Helper()
+	s.AssertRune(t, x, y, ch)
+	cell := s.GetCell(x, y)
+	if cell.Fg != fg || cell.Bg != bg {
+		t.Errorf("cell %d,%d: expected colors %d/%d, got %d/%d", x, y, fg, bg, cell.Fg, cell.Bg)
+	}
+}
+
+// AssertText fails the test if the text at x, y isn't txt
+func (s *Screen) AssertText(t testing.TB, x, y int, txt string) {
+	t.Helper()
+	s.AssertRegion(t, x, y, []string{txt})
+}
+
+// AssertRegion fails the test if the area starting at x, y doesn't match
+// the golden lines. The region is as wide as the longest golden line.
+func (s *Screen) AssertRegion(t testing.TB, x, y int, golden []string) {
+	t.Helper()
+	var w int
+	for _, line := range golden {
+		if l := len([]rune(line)); l > w {
+			w = l
+		}
+	}
+	want := make([]string, len(golden))
+	for idx, line := range golden {
+		want[idx] = line + strings.Repeat(" ", w-len([]rune(line)))
+	}
+	got := s.GetRegion(x, y, w, len(golden))
+	if diff := diffLines(want, got); diff != "" {
+		t.Errorf("region at %d,%d doesn't match:\n%s", x, y, diff)
+	}
+}
+
+// AssertGolden fails the test if the whole screen doesn't match the
+// text in the file at path. If UpdateGoldenEnv is set, the file is
+// written with the current screen instead.
+func (s *Screen) AssertGolden(t testing.TB, path string) {
+	t.Helper()
+	got := s.String() + "\n"
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("writing golden file: %s", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %s", err)
+	}
+	wantLines := strings.Split(strings.TrimSuffix(string(want), "\n"), "\n")
+	if diff := diffLines(wantLines, s.GetStrings()); diff != "" {
+		t.Errorf("screen doesn't match %s:\n%s", path, diff)
+	}
+}
+
+// diffLines returns a line by line description of the differences
+// between want and got, or an empty string if they are the same
+func diffLines(want, got []string) string {
+	var ret []string
+	for idx := 0; idx < len(want) || idx < len(got); idx++ {
+		var w, g string
+		if idx < len(want) {
+			w = want[idx]
+		}
+		if idx < len(got) {
+			g = got[idx]
+		}
+		if w != g {
+			ret = append(ret, fmt.Sprintf("line %d:\n  want |%s|\n  got  |%s|", idx, w, g))
+		}
+	}
+	return strings.Join(ret, "\n")
+}
+
+/* Event Builders */
+
+// Key returns a key press event for k
+func Key(k termbox.Key) termbox.Event {
+	return termbox.Event{Type: termbox.EventKey, Key: k}
+}
+
+// Rune returns a key press event for the character r
+func Rune(r rune) termbox.Event {
+	if r == ' ' {
+		return Key(termbox.KeySpace)
+	}
+	return termbox.Event{Type: termbox.EventKey, Ch: r}
+}
+
+// Runes returns a key press event for each character in str
+func Runes(str string) []termbox.Event {
+	var ret []termbox.Event
+	for _, r := range str {
+		ret = append(ret, Rune(r))
+	}
+	return ret
+}
+
+// Resize returns a resize event for a screen that is w by h
+func Resize(w, h int) termbox.Event {
+	return termbox.Event{Type: termbox.EventResize, Width: w, Height: h}
+}
+
+// Mouse returns a mouse event for button k at x, y
+func Mouse(k termbox.Key, x, y int) termbox.Event {
+	return termbox.Event{Type: termbox.EventMouse, Key: k, MouseX: x, MouseY: y}
+}
+
+// Click returns a left click at x, y
+func Click(x, y int) termbox.Event { return Mouse(termbox.MouseLeft, x, y) }
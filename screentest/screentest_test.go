@@ -0,0 +1,114 @@
+package screentest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/nsf/termbox-go"
+)
+
+// failT is a testing.TB that remembers failures instead of failing the test
+type failT struct {
+	testing.TB
+	failed bool
+	msg    string
+}
+
+func (t *failT) Helper() {}
+
+func (t *failT) Errorf(format string, args ...interface{}) {
+	t.failed, t.msg = true, fmt.Sprintf(format, args...)
+}
+
+func (t *failT) Fatalf(format string, args ...interface{}) { t.Errorf(format, args...) }
+
+func TestDrawLabel(t *testing.T) {
+	s := CreateScreen(10, 2)
+	s.Draw(termboxUtil.CreateLabel("Hello", 1, 0, 5, 1, termbox.ColorGreen, termbox.ColorBlack))
+	s.AssertText(t, 0, 0, " Hello    ")
+	s.AssertCell(t, 1, 0, 'H', termbox.ColorGreen, termbox.ColorBlack)
+	s.AssertRune(t, 0, 1, ' ')
+	if got := s.GetRegion(1, 0, 3, 1); got[0] != "Hel" {
+		t.Errorf("expected region \"Hel\", got %q", got[0])
+	}
+}
+
+func TestStepInputField(t *testing.T) {
+	s := CreateScreen(10, 1)
+	fld := termboxUtil.CreateInputField(0, 0, 10, 1, termbox.ColorWhite, termbox.ColorBlack)
+	for idx, ok := range s.Step(fld, Runes("abc")...) {
+		if !ok {
+			t.Errorf("event %d wasn't consumed", idx)
+		}
+	}
+	s.Step(fld, Key(termbox.KeyBackspace2), Rune('d'))
+	if fld.GetValue() != "abd" {
+		t.Errorf("expected value \"abd\", got %q", fld.GetValue())
+	}
+	s.AssertText(t, 0, 0, "abd")
+}
+
+func TestSendResize(t *testing.T) {
+	s := CreateScreen(10, 2)
+	s.Send(termboxUtil.CreateLabel("x", 0, 0, 1, 1, termbox.ColorWhite, termbox.ColorBlack), Resize(20, 4))
+	if w, h := s.Size(); w != 20 || h != 4 {
+		t.Errorf("expected the screen to be 20x4, got %dx%d", w, h)
+	}
+}
+
+func TestRunes(t *testing.T) {
+	evs := Runes("a é")
+	if len(evs) != 3 || evs[0].Ch != 'a' || evs[1].Key != termbox.KeySpace || evs[2].Ch != 'é' {
+		t.Errorf("unexpected events %+v", evs)
+	}
+}
+
+func TestAssertionsFail(t *testing.T) {
+	s := CreateScreen(5, 1)
+	s.Draw(termboxUtil.CreateLabel("abc", 0, 0, 3, 1, termbox.ColorWhite, termbox.ColorBlack))
+	ft := &failT{TB: t}
+	s.AssertText(ft, 0, 0, "abd")
+	if !ft.failed {
+		t.Error("expected AssertText to fail")
+	}
+	ft = &failT{TB: t}
+	s.AssertCell(ft, 0, 0, 'a', termbox.ColorRed, termbox.ColorBlack)
+	if !ft.failed {
+		t.Error("expected AssertCell to fail on colors")
+	}
+	ft = &failT{TB: t}
+	s.AssertRegion(ft, 0, 0, []string{"abc"})
+	if ft.failed {
+		t.Errorf("expected AssertRegion to pass: %s", ft.msg)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	s := CreateScreen(12, 3)
+	lbl := termboxUtil.CreateLabel("Golden", 0, 0, 11, 2, termbox.ColorWhite, termbox.ColorBlack)
+	lbl.SetBordered(true)
+	s.Draw(lbl)
+	s.AssertGolden(t, filepath.Join("testdata", "label.golden"))
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		return
+	}
+
+	ft := &failT{TB: t}
+	s.Draw(termboxUtil.CreateLabel("Silver", 1, 0, 10, 3, termbox.ColorWhite, termbox.ColorBlack))
+	s.AssertGolden(ft, filepath.Join("testdata", "label.golden"))
+	if !ft.failed {
+		t.Error("expected AssertGolden to fail")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	if d := diffLines([]string{"a", "b"}, []string{"a", "b"}); d != "" {
+		t.Errorf("expected no differences, got %q", d)
+	}
+	if d := diffLines([]string{"a"}, []string{"a", "b"}); d == "" {
+		t.Error("expected an extra line to be a difference")
+	}
+}
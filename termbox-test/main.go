@@ -1,59 +1,57 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"os"
-	"strconv"
-	"syscall"
 
 	"github.com/br0xen/termbox-util"
 
 	"github.com/nsf/termbox-go"
 )
 
-var keepRunning bool
-var initialized bool
 var frame *termboxUtil.Frame
 
 func main() {
-	keepRunning = true
-	err := termbox.Init()
-	if err != nil {
-		panic(err)
-	}
-	defer termbox.Close()
-	termbox.SetOutputMode(termbox.Output256)
-	mainLoop()
-}
+	record := flag.String("record", "", "write the session's events to `file`")
+	replay := flag.String("replay", "", "play back the events in `file`")
+	speed := flag.Float64("speed", 1, "how many times faster to play back, 0 for no waiting")
+	flag.Parse()
 
-func mainLoop() {
-	layoutAndDrawScreen()
-	for {
-		event := termbox.PollEvent()
-		if event.Type == termbox.EventKey {
-			if event.Key == termbox.KeyCtrlZ {
-				process, _ := os.FindProcess(os.Getpid())
-				termbox.Close()
-				process.Signal(syscall.SIGSTOP)
-				termbox.Init()
-			} else if event.Key == termbox.KeyCtrlC {
-				termbox.Close()
-				os.Exit(0)
-			}
-			keepRunning = handleEvent(event)
-			if !keepRunning {
-				break
-			}
-			layoutAndDrawScreen()
+	app := termboxUtil.CreateApp(nil)
+	app.SetOutputMode(termbox.Output256)
+	app.SetClearColors(0, termbox.ColorBlack)
+	app.SetLayout(layoutScreen)
+	app.SetOnEvent(handleEvent)
+	app.SetOnDraw(drawStatus)
+	app.SetDamageTracking(true)
+	if *record != "" {
+		f, err := os.Create(*record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
-		if event.Type == termbox.EventResize {
-			layoutAndDrawScreen()
+		defer f.Close()
+		app.SetRecorder(termboxUtil.CreateEventRecorder(f))
+	}
+	if *replay != "" {
+		rec, err := termboxUtil.LoadRecording(*replay)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+		p := termboxUtil.CreateEventPlayer(rec)
+		p.SetSpeed(*speed)
+		app.Replay(p)
+	}
+	if err := app.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
 
-func layoutAndDrawScreen() {
-	w, h := termbox.Size()
-	if !initialized {
+func layoutScreen(app *termboxUtil.App, w, h int) {
+	if frame == nil {
 		fg, bg := termbox.ColorWhite, termbox.ColorBlack
 		frame = termboxUtil.CreateFrame(1, 1, w-3, h-3, termbox.ColorWhite, termbox.ColorBlack)
 		/*
@@ -81,21 +79,28 @@ func layoutAndDrawScreen() {
 		},
 			1, frame.GetBottomY()+1, w-5, 7, fg, bg, termbox.ColorBlack, termbox.ColorGreen))
 		frame.GetLastControl().SetBordered(true)
-		frame.SetActiveFlag(true)
-
-		initialized = true
+		frame.SetActive(true)
+		app.SetRoot(frame)
+		return
 	}
-	termbox.Clear(0, termbox.ColorBlack)
-	drawScreen()
-	termboxUtil.DrawStringAtPoint(strconv.Itoa(frame.GetBottomY()), 0, h-1, termbox.ColorWhite, termbox.ColorBlack)
-	termbox.Flush()
+	frame.SetWidth(w - 3)
+	frame.SetHeight(h - 3)
 }
 
-func drawScreen() {
-	frame.Draw()
+func drawStatus(app *termboxUtil.App) {
+	_, h := termbox.Size()
+	// Padded so a shorter number covers a longer one drawn before it
+	termboxUtil.DrawStringAtPoint(fmt.Sprintf("%-4d", frame.GetBottomY()), 0, h-1, termbox.ColorWhite, termbox.ColorBlack)
 }
 
-func handleEvent(event termbox.Event) bool {
+func handleEvent(app *termboxUtil.App, event termbox.Event) bool {
+	if event.Type == termbox.EventMouse {
+		frame.HandleEvent(event)
+		return true
+	}
+	if event.Type != termbox.EventKey || event.Key == termbox.KeyCtrlC || event.Key == termbox.KeyCtrlZ {
+		return false
+	}
 	frame.HandleEvent(event)
 	for _, k := range frame.GetControls() {
 		switch v := k.(type) {
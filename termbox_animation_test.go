@@ -0,0 +1,94 @@
+package termboxUtil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func TestCursorBlink(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	s := screentest.CreateScreen(10, 1)
+	fld := termboxUtil.CreateInputField(0, 0, 10, 1, fg, bg)
+	fld.SetActive(true)
+	fld.SetCursorBlink(500 * time.Millisecond)
+	now := time.Now()
+	termboxUtil.TickControls(fld, now)
+	s.Draw(fld)
+	s.AssertCell(t, 0, 0, ' ', bg, fg)
+	if !termboxUtil.TickControls(fld, now.Add(600*time.Millisecond)) {
+		t.Fatal("expected the cursor to be hidden")
+	}
+	s.Draw(fld)
+	s.AssertCell(t, 0, 0, ' ', fg, bg)
+	// Typing shows it again straight away
+	s.Send(fld, screentest.Rune('a'))
+	s.Draw(fld)
+	s.AssertCell(t, 1, 0, ' ', bg, fg)
+}
+
+func TestMarqueeScrolls(t *testing.T) {
+	s := screentest.CreateScreen(5, 1)
+	m := termboxUtil.CreateMarquee("abcdefg", 0, 0, 5, termbox.ColorWhite, termbox.ColorBlack)
+	now := time.Now()
+	termboxUtil.TickControls(m, now)
+	s.Draw(m)
+	s.AssertText(t, 0, 0, "abcde")
+	termboxUtil.TickControls(m, now.Add(2*m.GetInterval()))
+	s.Draw(m)
+	s.AssertText(t, 0, 0, "cdefg")
+	termboxUtil.TickControls(m, now.Add(7*m.GetInterval()))
+	s.Draw(m)
+	s.AssertText(t, 0, 0, "   ab")
+
+	m.SetText("abc")
+	if m.IsScrolling() || termboxUtil.TickControls(m, now.Add(time.Hour)) {
+		t.Error("expected text that fits not to scroll")
+	}
+}
+
+func TestToastExpires(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	s := screentest.CreateScreen(20, 5)
+	frm := termboxUtil.CreateFrame(0, 0, 19, 4, fg, bg)
+	toast := termboxUtil.CreateToast(fg, bg)
+	frm.AddPlacedControl(toast, termboxUtil.PlaceAt(termboxUtil.AnchorBottomRight, termboxUtil.Auto(), termboxUtil.Auto()))
+	toast.Show("Saved", time.Second)
+	now := time.Now()
+	termboxUtil.TickControls(frm, now)
+	s.Draw(frm)
+	s.AssertRegion(t, 10, 0, []string{
+		"═════════╗",
+		"╔═══════╗║",
+		"║ Saved ║║",
+		"╚═══════╝║",
+		"═════════╝",
+	})
+	if termboxUtil.TickControls(frm, now.Add(time.Second/2)) {
+		t.Error("expected the toast to still be showing")
+	}
+	if !termboxUtil.TickControls(frm, now.Add(time.Second)) || toast.IsVisible() {
+		t.Fatal("expected the toast to go away")
+	}
+	s.Clear()
+	s.Draw(frm)
+	s.AssertText(t, 10, 2, "         ")
+}
+
+func TestBorderedMarquee(t *testing.T) {
+	s := screentest.CreateScreen(7, 3)
+	m := termboxUtil.CreateMarquee("abcdefg", 0, 0, 6, termbox.ColorWhite, termbox.ColorBlack)
+	m.SetBordered(true)
+	if m.GetHeight() != 2 {
+		t.Errorf("expected a bordered marquee to be 2 high, got %d", m.GetHeight())
+	}
+	s.Draw(m)
+	s.AssertRegion(t, 0, 0, []string{
+		"╔═════╗",
+		"║abcde║",
+		"╚═════╝",
+	})
+}
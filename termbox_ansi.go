@@ -0,0 +1,183 @@
+package termboxUtil
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// ParseANSIText splits str into runs of styled text, starting with the
+// colors fg and bg and following any ANSI SGR escape sequences in it.
+// 16 color, 256 color and truecolor (approximated to 256 colors) codes
+// are understood, along with bold, underline and reverse. Any other
+// escape sequences are dropped. The 256 color and truecolor codes need
+// termbox.Output256 to be drawn right, see App.SetOutputMode.
+func ParseANSIText(str string, fg, bg termbox.Attribute) []StyledRun {
+	var ret []StyledRun
+	var curr strings.Builder
+	useFg, useBg := fg, bg
+	flush := func() {
+		if curr.Len() > 0 {
+			ret = append(ret, StyledRun{Text: curr.String(), Fg: useFg, Bg: useBg})
+			curr.Reset()
+		}
+	}
+	for len(str) > 0 {
+		if str[0] != '\x1b' {
+			curr.WriteByte(str[0])
+			str = str[1:]
+			continue
+		}
+		if len(str) < 2 {
+			break
+		}
+		switch str[1] {
+		case '[':
+			// CSI: parameters then a final byte in 0x40-0x7E
+			end := 2
+			for end < len(str) && (str[end] < 0x40 || str[end] > 0x7E) {
+				end++
+			}
+			if end >= len(str) {
+				str = ""
+				continue
+			}
+			if str[end] == 'm' {
+				flush()
+				useFg, useBg = applySGR(str[2:end], useFg, useBg, fg, bg)
+			}
+			str = str[end+1:]
+		case ']':
+			// OSC: runs until BEL or ESC \
+			rest := str[2:]
+			end, skip := strings.IndexByte(rest, '\x07'), 1
+			if st := strings.Index(rest, "\x1b\\"); st >= 0 && (end < 0 || st < end) {
+				end, skip = st, 2
+			}
+			if end < 0 {
+				str = ""
+				continue
+			}
+			str = rest[end+skip:]
+		default:
+			// Two byte escape sequence
+			str = str[2:]
+		}
+	}
+	flush()
+	return ret
+}
+
+// applySGR applies the ';' separated SGR parameters params to fg and bg.
+// A reset goes back to baseFg and baseBg.
+func applySGR(params string, fg, bg, baseFg, baseBg termbox.Attribute) (termbox.Attribute, termbox.Attribute) {
+	const attrMask = termbox.AttrBold | termbox.AttrUnderline | termbox.AttrReverse
+	var codes []int
+	for _, p := range strings.Split(params, ";") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			n = 0
+		}
+		codes = append(codes, n)
+	}
+	attrs := fg & attrMask
+	fg, bg = fg&^attrMask, bg&^attrMask
+	for idx := 0; idx < len(codes); idx++ {
+		code := codes[idx]
+		switch {
+		case code == 0:
+			fg, bg, attrs = baseFg&^attrMask, baseBg&^attrMask, baseFg&attrMask
+		case code == 1:
+			attrs |= termbox.AttrBold
+		case code == 4:
+			attrs |= termbox.AttrUnderline
+		case code == 7:
+			attrs |= termbox.AttrReverse
+		case code == 22:
+			attrs &^= termbox.AttrBold
+		case code == 24:
+			attrs &^= termbox.AttrUnderline
+		case code == 27:
+			attrs &^= termbox.AttrReverse
+		case code >= 30 && code <= 37:
+			fg = termbox.Attribute(code-30) + termbox.ColorBlack
+		case code == 39:
+			fg = baseFg &^ attrMask
+		case code >= 40 && code <= 47:
+			bg = termbox.Attribute(code-40) + termbox.ColorBlack
+		case code == 49:
+			bg = baseBg &^ attrMask
+		case code >= 90 && code <= 97:
+			fg = termbox.Attribute(code-90+8) + termbox.ColorBlack
+		case code >= 100 && code <= 107:
+			bg = termbox.Attribute(code-100+8) + termbox.ColorBlack
+		case code == 38 || code == 48:
+			c, used, ok := parseExtendedColor(codes[idx+1:])
+			idx += used
+			if ok && code == 38 {
+				fg = c
+			} else if ok {
+				bg = c
+			}
+		}
+	}
+	return fg | attrs, bg
+}
+
+// parseExtendedColor parses the "5;n" or "2;r;g;b" that follows a 38 or 48
+// and returns the color, how many codes it used and whether it was valid
+func parseExtendedColor(codes []int) (termbox.Attribute, int, bool) {
+	if len(codes) >= 2 && codes[0] == 5 {
+		if codes[1] < 0 || codes[1] > 255 {
+			return 0, 2, false
+		}
+		return termbox.Attribute(codes[1] + 1), 2, true
+	}
+	if len(codes) >= 4 && codes[0] == 2 {
+		return termbox.Attribute(RGBTo256(codes[1], codes[2], codes[3]) + 1), 4, true
+	}
+	return 0, len(codes), false
+}
+
+// RGBTo256 returns the closest color in the 256 color palette to r, g, b
+func RGBTo256(r, g, b int) int {
+	clamp := func(v int) int {
+		if v < 0 {
+			return 0
+		} else if v > 255 {
+			return 255
+		}
+		return v
+	}
+	r, g, b = clamp(r), clamp(g), clamp(b)
+	// Grays are better matched by the grayscale ramp (232-255)
+	if r == g && g == b {
+		if r < 8 {
+			return 16
+		} else if r > 248 {
+			return 231
+		}
+		return 232 + (r-8)*24/247
+	}
+	toCube := func(v int) int {
+		if v < 48 {
+			return 0
+		} else if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	return 16 + 36*toCube(r) + 6*toCube(g) + toCube(b)
+}
+
+// StripANSI returns str with all of its escape sequences removed
+func StripANSI(str string) string {
+	return StyledRunsText(ParseANSIText(str, 0, 0))
+}
+
+// DrawANSIStringAtPoint draws str at x, y following any ANSI color
+// escape sequences in it, starting with the colors fg and bg
+func DrawANSIStringAtPoint(str string, x, y int, fg, bg termbox.Attribute) (int, int) {
+	return DrawStyledRunsAtPoint(ParseANSIText(str, fg, bg), x, y)
+}
@@ -0,0 +1,424 @@
+package termboxUtil
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// App owns termbox and the event loop for a root control. It passes events
+// on to the root, re-lays out the screen when the terminal is resized,
+// suspends on Ctrl-Z and quits on Ctrl-C. Focus is handled for the whole
+// tree of controls by a FocusManager, and mouse input is turned on.
+// Controls that animate are ticked at the frame rate (see SetFrameRate).
+type App struct {
+	root             Control
+	rootPlacement    *Placement
+	focus            *FocusManager
+	outputMode       termbox.OutputMode
+	inputMode        termbox.InputMode
+	clearFg, clearBg termbox.Attribute
+	running          bool
+	looping          int32 // running, for other goroutines to check
+	stopRequested    int32
+	inbuf            []byte
+	escDeadline      time.Time
+
+	updateMu      sync.Mutex
+	updates       []func()
+	wakeRequested int32
+	redraw        bool
+	damage        *damageTracker
+
+	// pollMu guards whether the event loop is waiting for an event and
+	// whether a termbox.Interrupt is on its way to it
+	pollMu       sync.Mutex
+	polling      bool
+	interrupting bool
+
+	frameRate     int
+	frameStop     chan struct{}
+	tickRequested int32
+
+	// timerMu guards the timers that belong to a control
+	timerMu     sync.Mutex
+	ownedTimers []*Timer
+
+	recorder  *EventRecorder
+	player    *EventPlayer
+	replaying bool
+	replayErr error
+
+	layout    func(*App, int, int)
+	onEvent   func(*App, termbox.Event) bool
+	onDraw    func(*App)
+	onQuit    func(*App) bool
+	onSuspend func(*App)
+	onResume  func(*App)
+}
+
+// CreateApp creates an app that runs the control root
+func CreateApp(root Control) *App {
+	a := App{
+		root:       root,
+		focus:      CreateFocusManager(root),
+		outputMode: termbox.OutputNormal,
+		inputMode:  termbox.InputEsc | termbox.InputMouse,
+		clearFg:    termbox.ColorDefault,
+		clearBg:    termbox.ColorDefault,
+		frameRate:  DefaultFrameRate,
+	}
+	return &a
+}
+
+// GetRoot returns the control the app is running
+func (a *App) GetRoot() Control { return a.root }
+
+// SetRoot sets the control the app is running
+func (a *App) SetRoot(root Control) {
+	a.root = root
+	a.focus = CreateFocusManager(root)
+	a.RedrawAll()
+	if a.running {
+		a.focus.Refresh()
+	}
+}
+
+// SetRootPlacement places the root on the screen, it's moved and sized to
+// fit every time the app is laid out, before the layout function is called
+func (a *App) SetRootPlacement(p Placement) { a.rootPlacement = &p }
+
+// ClearRootPlacement leaves the root wherever it is put
+func (a *App) ClearRootPlacement() { a.rootPlacement = nil }
+
+// GetFocusManager returns the focus manager for the root's controls
+func (a *App) GetFocusManager() *FocusManager { return a.focus }
+
+// GetOutputMode returns the termbox output mode the app runs in
+func (a *App) GetOutputMode() termbox.OutputMode { return a.outputMode }
+
+// SetOutputMode sets the termbox output mode the app runs in. It's
+// termbox.OutputNormal by default, which only has the 16 basic colors,
+// use termbox.Output256 for color numbers over 15 in markup (see
+// ParseColor) and 256 color or truecolor ANSI text.
+func (a *App) SetOutputMode(m termbox.OutputMode) {
+	a.outputMode = m
+	if a.running {
+		termbox.SetOutputMode(m)
+	}
+}
+
+// GetInputMode returns the termbox input mode the app runs in
+func (a *App) GetInputMode() termbox.InputMode { return a.inputMode }
+
+// SetInputMode sets the termbox input mode the app runs in
+func (a *App) SetInputMode(m termbox.InputMode) {
+	a.inputMode = m
+	if a.running {
+		termbox.SetInputMode(m)
+	}
+}
+
+// SetClearColors sets the colors the screen is cleared to before each draw
+func (a *App) SetClearColors(fg, bg termbox.Attribute) {
+	a.clearFg, a.clearBg = fg, bg
+}
+
+// SetLayout sets the function that positions the controls for a screen
+// that is w by h. It's called when the app starts and whenever the
+// terminal is resized or the app resumes.
+func (a *App) SetLayout(layout func(a *App, w, h int)) { a.layout = layout }
+
+// SetOnEvent sets a function that sees every event before the root does.
+// If it returns true the event has been handled and goes no further.
+func (a *App) SetOnEvent(onEvent func(a *App, event termbox.Event) bool) { a.onEvent = onEvent }
+
+// SetOnDraw sets a function that is called after the root has been drawn,
+// just before the screen is flushed
+func (a *App) SetOnDraw(onDraw func(a *App)) { a.onDraw = onDraw }
+
+// SetOnQuit sets a function that is called when the app is asked to quit.
+// If it returns false the app keeps running.
+func (a *App) SetOnQuit(onQuit func(a *App) bool) { a.onQuit = onQuit }
+
+// SetOnSuspend sets a function that is called just before the app is suspended
+func (a *App) SetOnSuspend(onSuspend func(a *App)) { a.onSuspend = onSuspend }
+
+// SetOnResume sets a function that is called after the app has been resumed
+func (a *App) SetOnResume(onResume func(a *App)) { a.onResume = onResume }
+
+// IsDamageTracking returns whether the app only redraws what has changed
+func (a *App) IsDamageTracking() bool { return a.damage != nil }
+
+// SetDamageTracking sets whether the app only redraws what has changed.
+// When it does, each draw finds the controls that are dirty (see
+// BaseControl.MarkDirty) and redraws just the areas they cover, along
+// with whatever overlaps them. Controls that handle an event are marked
+// dirty for you, anything changed some other way (in an update, or a
+// custom control's own fields) needs to be marked, or use RedrawAll.
+// The draw function (see SetOnDraw) is still called on every draw.
+func (a *App) SetDamageTracking(b bool) {
+	if !b {
+		a.damage = nil
+	} else if a.damage == nil {
+		a.damage = createDamageTracker()
+	}
+	a.redraw = true
+}
+
+// RedrawAll has the whole screen redrawn on the next draw
+func (a *App) RedrawAll() {
+	if a.damage != nil {
+		a.damage.full = true
+	}
+	a.redraw = true
+}
+
+// IsRunning returns whether the app's event loop is running
+func (a *App) IsRunning() bool { return a.running }
+
+// Run initializes termbox and runs the event loop until the app quits.
+// It returns an error if termbox can't be initialized or fails while running.
+func (a *App) Run() error {
+	if err := a.initTermbox(); err != nil {
+		return err
+	}
+	defer termbox.Close()
+	defer a.drainInterrupt()
+	atomic.StoreInt32(&a.stopRequested, 0)
+	a.running = true
+	atomic.StoreInt32(&a.looping, 1)
+	defer func() {
+		a.running = false
+		atomic.StoreInt32(&a.looping, 0)
+	}()
+	a.Layout()
+	a.focus.Refresh()
+	a.runUpdates()
+	a.Draw()
+	a.updateFrames()
+	defer a.stopFrames()
+	defer a.stopReplay()
+	for a.running {
+		if err := a.HandleEvent(a.nextEvent()); err != nil {
+			return err
+		}
+		a.runUpdates()
+		a.stopOrphanedTimers()
+		a.updateFrames()
+		if err := a.replayErr; err != nil {
+			a.replayErr = nil
+			return err
+		}
+		if a.running && a.redraw {
+			a.Draw()
+		}
+	}
+	return nil
+}
+
+// initTermbox starts termbox up in the app's modes
+func (a *App) initTermbox() error {
+	if err := termbox.Init(); err != nil {
+		return err
+	}
+	termbox.SetOutputMode(a.outputMode)
+	termbox.SetInputMode(a.inputMode)
+	return nil
+}
+
+// HandleEvent handles a single event the way Run does
+func (a *App) HandleEvent(event termbox.Event) error {
+	switch event.Type {
+	case termbox.EventError:
+		return event.Err
+	case termbox.EventInterrupt:
+		atomic.StoreInt32(&a.wakeRequested, 0)
+		a.runUpdates()
+		if atomic.CompareAndSwapInt32(&a.tickRequested, 1, 0) {
+			a.Tick(time.Now())
+		}
+		if atomic.CompareAndSwapInt32(&a.stopRequested, 1, 0) {
+			a.Quit()
+		}
+		return nil
+	}
+	if a.recorder != nil && !a.replaying {
+		if err := a.recorder.Record(event); err != nil {
+			return err
+		}
+	}
+	a.redraw = true
+	switch event.Type {
+	case termbox.EventResize:
+		// termbox.Size doesn't change until the next Clear or Flush
+		a.layoutTo(event.Width, event.Height)
+	}
+	if a.onEvent != nil && a.onEvent(a, event) {
+		return nil
+	}
+	if event.Type == termbox.EventKey {
+		switch event.Key {
+		case termbox.KeyCtrlC:
+			a.Quit()
+			return nil
+		case termbox.KeyCtrlZ:
+			return a.Suspend()
+		}
+	}
+	a.focus.HandleEvent(event)
+	return nil
+}
+
+// Layout places the root and runs the layout function for the current size of the screen
+func (a *App) Layout() { a.layoutTo(termbox.Size()) }
+
+// layoutTo places the root and runs the layout function for a screen that is w by h
+func (a *App) layoutTo(w, h int) {
+	a.RedrawAll()
+	if a.rootPlacement != nil && a.root != nil {
+		a.rootPlacement.Apply(a.root, 0, 0, w, h)
+	}
+	if a.layout != nil {
+		a.layout(a, w, h)
+	}
+}
+
+// Draw clears the screen, draws the root and flushes it to the terminal.
+// If damage is being tracked only what has changed is cleared and redrawn.
+func (a *App) Draw() {
+	a.redraw = false
+	if a.damage != nil {
+		a.damage.draw(a.root, a.clearFg, a.clearBg)
+	} else {
+		termbox.Clear(a.clearFg, a.clearBg)
+		if a.root != nil {
+			a.root.Draw()
+		}
+	}
+	if a.onDraw != nil {
+		a.onDraw(a)
+	}
+	termbox.Flush()
+}
+
+// Quit stops the event loop once the current event has been handled,
+// unless the quit function says not to. It should only be called from
+// the goroutine running the app, use Stop from anywhere else.
+func (a *App) Quit() {
+	if a.onQuit != nil && !a.onQuit(a) {
+		return
+	}
+	a.running = false
+}
+
+// Stop asks a running app to quit from another goroutine. It doesn't
+// wait for the app to stop, and does nothing if the app isn't running.
+func (a *App) Stop() {
+	atomic.StoreInt32(&a.stopRequested, 1)
+	a.interrupt()
+}
+
+// QueueUpdate runs f on the goroutine running the app, between events,
+// and redraws the screen afterwards. Controls aren't safe to change from
+// more than one goroutine, so anything a background goroutine wants to do
+// to them (like moving a ProgressBar along) should be queued with this.
+// It can be called from any goroutine and doesn't wait for f to run.
+// Updates queued before the app is run are run when it starts.
+func (a *App) QueueUpdate(f func()) {
+	a.updateMu.Lock()
+	a.updates = append(a.updates, f)
+	a.updateMu.Unlock()
+	a.wake()
+}
+
+// wake interrupts the event loop, if it hasn't been already, so it
+// picks up queued updates and ticks
+func (a *App) wake() {
+	if atomic.CompareAndSwapInt32(&a.wakeRequested, 0, 1) {
+		a.interrupt()
+	}
+}
+
+// interrupt interrupts the event loop if it's waiting for an event. If
+// it isn't, it checks for wakes and stops before it waits again.
+func (a *App) interrupt() {
+	a.pollMu.Lock()
+	defer a.pollMu.Unlock()
+	if a.polling && !a.interrupting {
+		a.interrupting = true
+		// Interrupt blocks until the event loop gets it
+		go termbox.Interrupt()
+	}
+}
+
+// nextEvent waits for the next event, or returns an interrupt straight
+// away if the app has been woken or stopped since it last looked
+func (a *App) nextEvent() termbox.Event {
+	a.pollMu.Lock()
+	if atomic.LoadInt32(&a.wakeRequested) == 1 || atomic.LoadInt32(&a.stopRequested) == 1 {
+		a.pollMu.Unlock()
+		return termbox.Event{Type: termbox.EventInterrupt}
+	}
+	a.polling = true
+	a.pollMu.Unlock()
+	event := a.pollEvent()
+	a.pollMu.Lock()
+	a.polling = false
+	if event.Type == termbox.EventInterrupt {
+		a.interrupting = false
+	}
+	a.pollMu.Unlock()
+	return event
+}
+
+// drainInterrupt waits for an interrupt that's still on its way to the
+// event loop, so it isn't left blocked when termbox is closed
+func (a *App) drainInterrupt() {
+	a.pollMu.Lock()
+	pending := a.interrupting
+	a.interrupting = false
+	a.pollMu.Unlock()
+	for pending && termbox.PollEvent().Type != termbox.EventInterrupt {
+	}
+}
+
+// runUpdates runs every update that has been queued, in order
+func (a *App) runUpdates() {
+	a.updateMu.Lock()
+	updates := a.updates
+	a.updates = nil
+	a.updateMu.Unlock()
+	for _, f := range updates {
+		f()
+		a.redraw = true
+	}
+}
+
+// Suspend gives the terminal back to the shell and stops the process
+// (like Ctrl-Z would in any other program). Once the process is
+// continued termbox is started back up and the screen is re-laid out.
+// It's a no-op on platforms without job control.
+func (a *App) Suspend() error {
+	if !canSuspend {
+		return nil
+	}
+	if a.onSuspend != nil {
+		a.onSuspend(a)
+	}
+	a.drainInterrupt()
+	termbox.Close()
+	if err := suspendProcess(); err != nil {
+		return err
+	}
+	if err := a.initTermbox(); err != nil {
+		return err
+	}
+	a.Layout()
+	if a.onResume != nil {
+		a.onResume(a)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package termboxUtil
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestStopAndWakeWithoutRunning(t *testing.T) {
+	a := CreateApp(nil)
+	before := runtime.NumGoroutine()
+	done := make(chan struct{})
+	go func() {
+		a.Stop()
+		a.QueueUpdate(func() {})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop and QueueUpdate blocked on an app that isn't running")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("expected no goroutines to be left behind, %d before and %d after", before, n)
+	}
+	// The loop picks the wake up the next time it looks for an event
+	if ev := a.nextEvent(); ev.Type != termbox.EventInterrupt {
+		t.Errorf("expected an interrupt, got %+v", ev)
+	}
+}
+
+func TestReplayIsntRecorded(t *testing.T) {
+	var buf bytes.Buffer
+	a := CreateApp(CreateInputField(0, 0, 10, 1, termbox.ColorWhite, termbox.ColorBlack))
+	a.SetRecorder(CreateEventRecorder(&buf))
+	p := CreateEventPlayer(Recording{
+		{Event: termbox.Event{Type: termbox.EventKey, Ch: 'a'}},
+		{Event: termbox.Event{Type: termbox.EventKey, Ch: 'b'}},
+	})
+	p.SetSpeed(0)
+	a.Replay(p)
+	for p.IsPlaying() {
+		time.Sleep(time.Millisecond)
+	}
+	a.runUpdates()
+	if buf.Len() != 0 {
+		t.Errorf("expected replayed events not to be recorded, got %q", buf.String())
+	}
+	if err := a.HandleEvent(termbox.Event{Type: termbox.EventKey, Ch: 'c'}); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 1 {
+		t.Errorf("expected the event from the terminal to be recorded, got %q", buf.String())
+	}
+}
+
+func TestResizeLaysOutToNewSize(t *testing.T) {
+	frm := CreateFrame(0, 0, 10, 5, termbox.ColorWhite, termbox.ColorBlack)
+	a := CreateApp(frm)
+	a.SetRootPlacement(Fill())
+	var gotW, gotH int
+	a.SetLayout(func(_ *App, w, h int) { gotW, gotH = w, h })
+	if err := a.HandleEvent(termbox.Event{Type: termbox.EventResize, Width: 40, Height: 12}); err != nil {
+		t.Fatal(err)
+	}
+	if gotW != 40 || gotH != 12 {
+		t.Errorf("expected the layout function to get 40x12, got %dx%d", gotW, gotH)
+	}
+	// The frame's border goes at x+width and y+height, on the last column and row
+	if frm.GetWidth() != 39 || frm.GetHeight() != 11 {
+		t.Errorf("expected the frame to fill the new screen at 39x11, got %dx%d", frm.GetWidth(), frm.GetHeight())
+	}
+}
+
+func TestQueueUpdateFromGoroutines(t *testing.T) {
+	bar := CreateProgressBar(100, 0, 0, termbox.ColorWhite, termbox.ColorBlack)
+	a := CreateApp(bar)
+	var wg sync.WaitGroup
+	for k := 0; k < 10; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				a.QueueUpdate(bar.IncrProgress)
+			}
+		}()
+	}
+	wg.Wait()
+	if bar.GetProgress() != 0 {
+		t.Fatal("expected queued updates not to run before the app gets to them")
+	}
+	var order []int
+	a.QueueUpdate(func() { order = append(order, 1) })
+	a.QueueUpdate(func() { order = append(order, 2) })
+	a.redraw = false
+	if err := a.HandleEvent(termbox.Event{Type: termbox.EventInterrupt}); err != nil {
+		t.Fatal(err)
+	}
+	if bar.GetProgress() != 50 {
+		t.Errorf("expected all 50 updates to run, got %d", bar.GetProgress())
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("expected updates to run in the order they were queued, got %v", order)
+	}
+	if !a.redraw {
+		t.Error("expected running updates to redraw the screen")
+	}
+}
@@ -0,0 +1,129 @@
+//go:build !windows
+// +build !windows
+
+package termboxUtil
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nsf/termbox-go"
+)
+
+const canSuspend = true
+
+// backTabSequence is what terminals send for Shift-Tab
+const backTabSequence = "\x1b[Z"
+
+// suspendProcess stops the process until it's continued (with fg or SIGCONT)
+func suspendProcess() error {
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return process.Signal(syscall.SIGSTOP)
+}
+
+// escWaitDelay is how long to wait for the rest of an escape sequence
+// that has been split across reads before parsing what there is of it
+const escWaitDelay = 100 * time.Millisecond
+
+// pollEvent waits for the next event. The terminal input is read raw so
+// that Shift-Tab, which termbox doesn't know about, can be turned into
+// KeyBackTab. Everything else is parsed the way termbox would.
+func (a *App) pollEvent() termbox.Event {
+	var data [256]byte
+	for {
+		if event, ok := a.nextInput(time.Now()); ok {
+			return event
+		}
+		var timer *time.Timer
+		if !a.escDeadline.IsZero() {
+			// Wake up to give up on the rest of the sequence
+			timer = time.AfterFunc(time.Until(a.escDeadline), a.interrupt)
+		}
+		event := termbox.PollRawEvent(data[:])
+		if timer != nil {
+			timer.Stop()
+		}
+		if event.Type != termbox.EventRaw {
+			return event
+		}
+		a.inbuf = append(a.inbuf, data[:event.N]...)
+	}
+}
+
+// nextInput parses the next event out of the input read so far, if there
+// is a whole one. An escape sequence that looks cut off is waited on until
+// escWaitDelay has passed since it started, and then parsed as it is.
+func (a *App) nextInput(now time.Time) (termbox.Event, bool) {
+	for len(a.inbuf) > 0 {
+		if escapeIncomplete(a.inbuf) {
+			if a.escDeadline.IsZero() {
+				a.escDeadline = now.Add(escWaitDelay)
+			}
+			if now.Before(a.escDeadline) {
+				return termbox.Event{}, false
+			}
+		}
+		a.escDeadline = time.Time{}
+		if strings.HasPrefix(string(a.inbuf), backTabSequence) {
+			a.inbuf = a.inbuf[len(backTabSequence):]
+			return termbox.Event{Type: termbox.EventKey, Key: KeyBackTab}, true
+		}
+		event := termbox.ParseEvent(a.inbuf)
+		if event.N > 0 {
+			a.inbuf = a.inbuf[event.N:]
+			if event.Type != termbox.EventNone {
+				return event, true
+			}
+			continue
+		}
+		if len(a.inbuf) < utf8.UTFMax {
+			// Probably the start of a rune, wait for the rest of it
+			break
+		}
+		// Skip anything termbox can't make sense of
+		a.inbuf = a.inbuf[1:]
+	}
+	return termbox.Event{}, false
+}
+
+// escapeIncomplete returns whether buf starts with an escape sequence
+// that hasn't all been read yet
+func escapeIncomplete(buf []byte) bool {
+	if len(buf) == 0 || buf[0] != '\x1b' {
+		return false
+	}
+	if len(buf) == 1 {
+		return true
+	}
+	switch buf[1] {
+	case 'O':
+		// SS3 sequences have one more byte
+		return len(buf) < 3
+	case '[':
+	default:
+		return false
+	}
+	switch {
+	case len(buf) == 2:
+		return true
+	case buf[2] == '[':
+		// Linux console function keys
+		return len(buf) < 4
+	case buf[2] == 'M':
+		// X10 mouse events have three more bytes
+		return len(buf) < 6
+	}
+	// CSI sequences are parameter and intermediate bytes up to a final byte
+	for _, b := range buf[2:] {
+		if b < 0x20 || b > 0x3f {
+			return false
+		}
+	}
+	return true
+}
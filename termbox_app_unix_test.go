@@ -0,0 +1,66 @@
+//go:build !windows
+// +build !windows
+
+package termboxUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestEscapeIncomplete(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want bool
+	}{
+		{"", false},
+		{"a", false},
+		{"\x1b", true},
+		{"\x1bO", true},
+		{"\x1bOP", false},
+		{"\x1b[", true},
+		{"\x1b[1;5", true},
+		{"\x1b[1;5A", false},
+		{"\x1b[Z", false},
+		{"\x1b[<0;10", true},
+		{"\x1b[<0;10;5M", false},
+		{"\x1b[M ", true},
+		{"\x1b[M !!", false},
+		{"\x1b[[", true},
+		{"\x1b[[A", false},
+		{"\x1ba", false},
+	} {
+		if got := escapeIncomplete([]byte(tc.in)); got != tc.want {
+			t.Errorf("escapeIncomplete(%q): expected %v, got %v", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestNextInputWaitsForSplitSequence(t *testing.T) {
+	a := CreateApp(nil)
+	now := time.Now()
+	a.inbuf = []byte("\x1b[")
+	if ev, ok := a.nextInput(now); ok {
+		t.Fatalf("expected to wait for the rest of the sequence, got %+v", ev)
+	}
+	a.inbuf = append(a.inbuf, 'Z')
+	ev, ok := a.nextInput(now.Add(time.Millisecond))
+	if !ok || ev.Key != KeyBackTab {
+		t.Fatalf("expected KeyBackTab, got %+v", ev)
+	}
+	if len(a.inbuf) != 0 {
+		t.Errorf("expected the whole sequence to be used, %q is left", a.inbuf)
+	}
+
+	// A lone escape is Esc once nothing else has come in time
+	a.inbuf = []byte("\x1b")
+	if _, ok := a.nextInput(now); ok {
+		t.Fatal("expected to wait for more input after an escape")
+	}
+	ev, ok = a.nextInput(now.Add(escWaitDelay))
+	if !ok || ev.Key != termbox.KeyEsc {
+		t.Fatalf("expected KeyEsc, got %+v", ev)
+	}
+}
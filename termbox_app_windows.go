@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+const canSuspend = false
+
+// suspendProcess does nothing, there's no job control on windows
+func suspendProcess() error { return nil }
+
+// pollEvent waits for the next event
+func (a *App) pollEvent() termbox.Event { return termbox.PollEvent() }
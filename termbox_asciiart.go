@@ -8,47 +8,18 @@ import (
 
 // ASCIIArt is a []string with more functions
 type ASCIIArt struct {
-	id                 string
-	contents           []string
-	x, y               int
-	bg, fg             termbox.Attribute
-	activeFg, activeBg termbox.Attribute
-	bordered           bool
-	tabSkip            bool
-	active             bool
+	BaseControl
+	contents   []string
+	textFormat TextFormat
 }
 
 // CreateASCIIArt Create an ASCII art object from a string slice
 func CreateASCIIArt(c []string, x, y int, fg, bg termbox.Attribute) *ASCIIArt {
-	i := ASCIIArt{contents: c, x: x, y: y, fg: fg, bg: bg, bordered: false, tabSkip: true}
-	i.activeFg, i.activeBg = fg, bg
+	i := ASCIIArt{BaseControl: CreateBaseControl(x, y, 0, 0, fg, bg), contents: c}
+	i.tabSkip = true
 	return &i
 }
 
-// GetID returns this control's ID
-func (i *ASCIIArt) GetID() string { return i.id }
-
-// SetID sets this control's ID
-func (i *ASCIIArt) SetID(newID string) {
-	i.id = newID
-}
-
-// GetX Return the x position of the modal
-func (i *ASCIIArt) GetX() int { return i.x }
-
-// SetX set the x position of the modal to x
-func (i *ASCIIArt) SetX(x int) {
-	i.x = x
-}
-
-// GetY Return the y position of the modal
-func (i *ASCIIArt) GetY() int { return i.y }
-
-// SetY Set the y position of the modal to y
-func (i *ASCIIArt) SetY(y int) {
-	i.y = y
-}
-
 // GetHeight Returns the number of strings in the contents slice
 func (i *ASCIIArt) GetHeight() int {
 	return len(i.contents)
@@ -63,6 +34,7 @@ func (i *ASCIIArt) SetHeight(h int) {
 			i.contents = append(i.contents, "")
 		}
 	}
+	i.dirty = true
 }
 
 // GetWidth Returns the number of strings in the contents slice
@@ -70,34 +42,48 @@ func (i *ASCIIArt) GetWidth() int {
 	// Find the longest line
 	var ret int
 	for j := range i.contents {
-		if len(i.contents[j]) > ret {
-			ret = len(i.contents[j])
+		if i.lineWidth(i.contents[j]) > ret {
+			ret = i.lineWidth(i.contents[j])
 		}
 	}
 	return ret
 }
 
 // SetWidth Sets all lines in the contents to width w
+// Lines that aren't TextPlain are only padded, never truncated,
+// so that their escape sequences or style tags aren't cut in half
 func (i *ASCIIArt) SetWidth(w int) {
 	// Find the longest line
 	for j := range i.contents {
-		mkUp := w - len(i.contents[j])
+		mkUp := w - i.lineWidth(i.contents[j])
 		if mkUp > 0 {
 			i.contents[j] = i.contents[j] + strings.Repeat(" ", mkUp)
-		} else {
-			i.contents[j] = i.contents[j][:w]
+		} else if i.textFormat == TextPlain {
+			i.contents[j] = TruncateText(i.contents[j], w)
 		}
 	}
+	i.dirty = true
+}
+
+// lineWidth returns the number of cells line takes up once it's drawn
+func (i *ASCIIArt) lineWidth(line string) int {
+	return FormattedTextWidth(line, i.textFormat)
 }
 
-func (i *ASCIIArt) SetActiveFgColor(fg termbox.Attribute) { i.activeFg = fg }
-func (i *ASCIIArt) SetActiveBgColor(bg termbox.Attribute) { i.activeBg = bg }
-func (i *ASCIIArt) SetActive(a bool)                      { i.active = a }
-func (i *ASCIIArt) IsActive() bool                        { return i.active }
+// GetTextFormat returns how the lines of art are interpreted
+func (i *ASCIIArt) GetTextFormat() TextFormat { return i.textFormat }
+
+// SetTextFormat sets how the lines of art are interpreted,
+// TextANSI lets it show colored output from other programs
+func (i *ASCIIArt) SetTextFormat(f TextFormat) {
+	i.textFormat = f
+	i.dirty = true
+}
 
 // SetContents Sets the contents of i to c
 func (i *ASCIIArt) SetContents(c []string) {
 	i.contents = c
+	i.dirty = true
 }
 
 // GetContents returns the ascii art
@@ -110,22 +96,7 @@ func (i *ASCIIArt) SetContentLine(s string, idx int) {
 	if idx >= 0 && idx < len(i.contents) {
 		i.contents[idx] = s
 	}
-}
-
-// GetFgColor returns the foreground color
-func (i *ASCIIArt) GetFgColor() termbox.Attribute { return i.fg }
-
-// SetFgColor sets the foreground color
-func (i *ASCIIArt) SetFgColor(fg termbox.Attribute) {
-	i.fg = fg
-}
-
-// GetBgColor returns the background color
-func (i *ASCIIArt) GetBgColor() termbox.Attribute { return i.bg }
-
-// SetBgColor sets the current background color
-func (i *ASCIIArt) SetBgColor(bg termbox.Attribute) {
-	i.bg = bg
+	i.dirty = true
 }
 
 // Align Align the Ascii art over width width with alignment a
@@ -134,46 +105,65 @@ func (i *ASCIIArt) Align(a TextAlignment, width int) {
 	var newContents []string
 	incomingLength := 0
 	for _, line := range i.contents {
-		if len(line) > incomingLength {
-			incomingLength = len(line)
+		if i.lineWidth(line) > incomingLength {
+			incomingLength = i.lineWidth(line)
+		}
+	}
+	// Work out the padding from the drawn widths, since the lines
+	// may have escape sequences that AlignText would count
+	numSpaces := width - incomingLength
+	var leftPad int
+	switch a {
+	case AlignCenter:
+		if numSpaces/2 > 0 {
+			leftPad = numSpaces / 2
+		}
+	case AlignRight:
+		if numSpaces > 0 {
+			leftPad = numSpaces
 		}
 	}
+	rightPad := numSpaces - leftPad
+	if a == AlignCenter {
+		rightPad = leftPad
+	}
 	for _, line := range i.contents {
-		newContents = append(newContents, AlignText(AlignText(line, incomingLength, AlignLeft), width, a))
+		line = strings.Repeat(" ", leftPad) + line + strings.Repeat(" ", incomingLength-i.lineWidth(line))
+		if rightPad > 0 {
+			line += strings.Repeat(" ", rightPad)
+		}
+		newContents = append(newContents, line)
 	}
 	i.contents = newContents
+	i.dirty = true
 }
 
-// IsBordered returns whether this modal is bordered or not
-func (i *ASCIIArt) IsBordered() bool {
-	return i.bordered
+// ApplyTheme sets the colors of the control from the theme t
+func (i *ASCIIArt) ApplyTheme(t *Theme) {
+	i.fg, i.bg = t.Base.Fg, t.Base.Bg
+	i.activeFg, i.activeBg = t.Active.Fg, t.Active.Bg
 }
 
-// SetBordered sets whether we render a border around the frame
-func (i *ASCIIArt) SetBordered(b bool) {
-	i.bordered = b
-}
-
-// IsTabSkipped returns whether this modal has it's tabskip flag set
-func (i *ASCIIArt) IsTabSkipped() bool {
-	return i.tabSkip
+// HandleEvent accepts the termbox event and returns whether it was consumed
+func (i *ASCIIArt) HandleEvent(event termbox.Event) bool {
+	return false
 }
 
-// SetTabSkip sets the tabskip flag for this control
-func (i *ASCIIArt) SetTabSkip(b bool) {
-	i.tabSkip = b
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (i *ASCIIArt) DrawToStrings() []string {
+	return DrawControlToBuffer(i).GetStrings()
 }
 
-// HandleEvent accepts the termbox event and returns whether it was consumed
-func (i *ASCIIArt) HandleEvent(event termbox.Event) bool {
-	return false
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (i *ASCIIArt) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(i).GetCells()
 }
 
 // Draw outputs the input field on the screen
 func (i *ASCIIArt) Draw() {
 	drawX, drawY := i.x, i.y
 	for _, line := range i.contents {
-		DrawStringAtPoint(line, drawX, drawY, i.fg, i.bg)
+		DrawFormattedStringAtPoint(line, i.textFormat, drawX, drawY, i.fg, i.bg)
 		drawY++
 	}
 }
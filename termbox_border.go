@@ -0,0 +1,159 @@
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+// BorderSide is a set of flags for which sides of a border get drawn
+type BorderSide int
+
+const (
+	// BorderTop draws the top of the border
+	BorderTop BorderSide = 1 << iota
+	// BorderBottom draws the bottom of the border
+	BorderBottom
+	// BorderLeft draws the left side of the border
+	BorderLeft
+	// BorderRight draws the right side of the border
+	BorderRight
+	// BorderAllSides draws every side of the border
+	BorderAllSides = BorderTop | BorderBottom | BorderLeft | BorderRight
+)
+
+// BorderStyle is the set of runes used to draw a border, which
+// sides of it are drawn and where a title goes on the top
+type BorderStyle struct {
+	Name                            string
+	TopLeft, Top, TopRight          rune
+	Left, Right                     rune
+	BottomLeft, Bottom, BottomRight rune
+	// Scroll is the rune used on the right side to show the scroll percentage
+	Scroll     rune
+	Sides      BorderSide
+	TitleAlign TextAlignment
+}
+
+// The built in border styles
+var (
+	BorderSingle  = BorderStyle{"single", '┌', '─', '┐', '│', '│', '└', '─', '┘', '▒', BorderAllSides, AlignLeft}
+	BorderDouble  = BorderStyle{"double", '╔', '═', '╗', '║', '║', '╚', '═', '╝', '▒', BorderAllSides, AlignLeft}
+	BorderRounded = BorderStyle{"rounded", '╭', '─', '╮', '│', '│', '╰', '─', '╯', '▒', BorderAllSides, AlignLeft}
+	BorderHeavy   = BorderStyle{"heavy", '┏', '━', '┓', '┃', '┃', '┗', '━', '┛', '▒', BorderAllSides, AlignLeft}
+	BorderDashed  = BorderStyle{"dashed", '┌', '╌', '┐', '╎', '╎', '└', '╌', '┘', '▒', BorderAllSides, AlignLeft}
+	BorderASCII   = BorderStyle{"ascii", '+', '-', '+', '|', '|', '+', '-', '+', '#', BorderAllSides, AlignLeft}
+	// BorderNone doesn't draw anything but a title
+	BorderNone = BorderStyle{Name: "none"}
+)
+
+// DefaultBorderStyle is the style used by controls that haven't had one set
+var DefaultBorderStyle = BorderDouble
+
+// CreateBorderStyle creates a style named name from a custom set of runes
+func CreateBorderStyle(name string, topLeft, top, topRight, left, right, bottomLeft, bottom, bottomRight rune) BorderStyle {
+	return BorderStyle{
+		Name:    name,
+		TopLeft: topLeft, Top: top, TopRight: topRight,
+		Left: left, Right: right,
+		BottomLeft: bottomLeft, Bottom: bottom, BottomRight: bottomRight,
+		Scroll: '▒', Sides: BorderAllSides, TitleAlign: AlignLeft,
+	}
+}
+
+// GetBorderStyleByName returns the built in style with the name n
+func GetBorderStyleByName(n string) (BorderStyle, bool) {
+	for _, s := range []BorderStyle{BorderSingle, BorderDouble, BorderRounded, BorderHeavy, BorderDashed, BorderASCII, BorderNone} {
+		if s.Name == n {
+			return s, true
+		}
+	}
+	return BorderStyle{}, false
+}
+
+// WithSides returns a copy of the style that only draws the sides s
+func (s BorderStyle) WithSides(sides BorderSide) BorderStyle {
+	s = s.resolve()
+	s.Sides = sides
+	return s
+}
+
+// WithTitleAlign returns a copy of the style with the title aligned a
+func (s BorderStyle) WithTitleAlign(a TextAlignment) BorderStyle {
+	s = s.resolve()
+	s.TitleAlign = a
+	return s
+}
+
+// resolve returns the DefaultBorderStyle for the zero value
+func (s BorderStyle) resolve() BorderStyle {
+	if s == (BorderStyle{}) {
+		return DefaultBorderStyle
+	}
+	return s
+}
+
+// corner picks the rune for a corner between two sides
+func (s BorderStyle) corner(r rune, side1 BorderSide, side1Rune rune, side2 BorderSide, side2Rune rune) rune {
+	has1, has2 := s.Sides&side1 != 0, s.Sides&side2 != 0
+	switch {
+	case has1 && has2:
+		return r
+	case has1:
+		return side1Rune
+	case has2:
+		return side2Rune
+	}
+	return 0
+}
+
+// DrawStyledBorder draws a border in the style s around the area inside x1,y1 -> x2, y2
+// with title on the top
+func DrawStyledBorder(x1, y1, x2, y2 int, s BorderStyle, title string, fg, bg termbox.Attribute) {
+	s = s.resolve()
+	setCell := func(x, y int, r rune) {
+		if r != 0 {
+			SetCell(x, y, r, fg, bg)
+		}
+	}
+	fill := func(r rune, fx1, fy1, fx2, fy2 int) {
+		if r != 0 {
+			FillWithChar(r, fx1, fy1, fx2, fy2, fg, bg)
+		}
+	}
+	if s.Sides&BorderTop != 0 {
+		fill(s.Top, x1+1, y1, x2-1, y1)
+	}
+	if s.Sides&BorderBottom != 0 {
+		fill(s.Bottom, x1+1, y2, x2-1, y2)
+	}
+	if s.Sides&BorderLeft != 0 {
+		fill(s.Left, x1, y1+1, x1, y2-1)
+	}
+	if s.Sides&BorderRight != 0 {
+		fill(s.Right, x2, y1+1, x2, y2-1)
+	}
+	setCell(x1, y1, s.corner(s.TopLeft, BorderTop, s.Top, BorderLeft, s.Left))
+	setCell(x2, y1, s.corner(s.TopRight, BorderTop, s.Top, BorderRight, s.Right))
+	setCell(x1, y2, s.corner(s.BottomLeft, BorderBottom, s.Bottom, BorderLeft, s.Left))
+	setCell(x2, y2, s.corner(s.BottomRight, BorderBottom, s.Bottom, BorderRight, s.Right))
+
+	if title != "" {
+		title = TruncateText(title, x2-x1-1)
+		titleX := x1 + 1
+		switch s.TitleAlign {
+		case AlignCenter:
+			titleX = x1 + (x2-x1+1-TextWidth(title))/2
+		case AlignRight:
+			titleX = x2 - TextWidth(title)
+		}
+		DrawStringAtPoint(title, titleX, y1, fg, bg)
+	}
+}
+
+// DrawStyledBorderWithPct draws a border in the style s with title on the top
+// and a scroll indicator pct of the way down the right side
+func DrawStyledBorderWithPct(x1, y1, x2, y2 int, s BorderStyle, title string, pct float64, fg, bg termbox.Attribute) {
+	s = s.resolve()
+	DrawStyledBorder(x1, y1, x2, y2, s, title, fg, bg)
+	if s.Scroll != 0 {
+		pctY := int(((float64(y2)-float64(y1)-2)*pct)+float64(y1)) + 1
+		SetCell(x2, pctY, s.Scroll, fg, bg)
+	}
+}
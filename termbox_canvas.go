@@ -0,0 +1,291 @@
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+// Canvas is something that cells can be drawn to.
+// All of the output helpers and controls draw through the
+// current canvas (see SetCanvas), which defaults to the termbox screen.
+type Canvas interface {
+	SetCell(x, y int, ch rune, fg, bg termbox.Attribute)
+	GetCell(x, y int) termbox.Cell
+	Size() (int, int)
+	// SubCanvas returns a canvas for the region at x, y that is w by h.
+	// Coordinates on the returned canvas are relative to x, y and
+	// anything drawn outside of the region is dropped.
+	SubCanvas(x, y, w, h int) Canvas
+}
+
+// drawable is anything that can be drawn at a position
+type drawable interface {
+	GetX() int
+	GetY() int
+	GetWidth() int
+	GetHeight() int
+	Draw()
+}
+
+// currentCanvas is where all drawing goes
+var currentCanvas Canvas = TermboxCanvas{}
+
+// GetCanvas returns the canvas that is currently being drawn to
+func GetCanvas() Canvas { return currentCanvas }
+
+// SetCanvas sets the canvas that everything is drawn to and
+// returns the previous one so that it can be restored.
+// Passing nil resets drawing to the termbox screen.
+func SetCanvas(c Canvas) Canvas {
+	prev := currentCanvas
+	if c == nil {
+		c = TermboxCanvas{}
+	}
+	currentCanvas = c
+	return prev
+}
+
+// DrawToCanvas draws the control t on the canvas cnv, then
+// restores the previous canvas
+func DrawToCanvas(cnv Canvas, t drawable) {
+	prev := SetCanvas(cnv)
+	t.Draw()
+	SetCanvas(prev)
+}
+
+// DrawClipped draws t on the current canvas, dropping anything that
+// falls outside of the area at x, y that is w by h. Clipping nests, so a
+// control drawn inside another clipped control is held to both areas.
+func DrawClipped(t drawable, x, y, w, h int) {
+	if currentDamage != nil {
+		currentDamage.drawClipped(t, x, y, w, h)
+		return
+	}
+	prev := SetCanvas(ClipCanvas(currentCanvas, x, y, w, h))
+	t.Draw()
+	SetCanvas(prev)
+}
+
+/* TermboxCanvas */
+
+// TermboxCanvas is a Canvas that draws directly to the termbox screen
+type TermboxCanvas struct{}
+
+// SetCell sets the termbox cell at x, y
+func (c TermboxCanvas) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	termbox.SetCell(x, y, ch, fg, bg)
+}
+
+// GetCell returns the termbox cell at x, y
+func (c TermboxCanvas) GetCell(x, y int) termbox.Cell {
+	w, h := termbox.Size()
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return termbox.Cell{}
+	}
+	return termbox.CellBuffer()[y*w+x]
+}
+
+// Size returns the size of the termbox screen
+func (c TermboxCanvas) Size() (int, int) { return termbox.Size() }
+
+// SubCanvas returns a region of the termbox screen
+func (c TermboxCanvas) SubCanvas(x, y, w, h int) Canvas {
+	return createSubCanvas(c, x, y, w, h)
+}
+
+/* BufferCanvas */
+
+// BufferCanvas is an in-memory grid of cells
+type BufferCanvas struct {
+	width, height int
+	cells         []termbox.Cell
+}
+
+// CreateBufferCanvas creates an empty in-memory canvas that is w by h
+func CreateBufferCanvas(w, h int) *BufferCanvas {
+	c := BufferCanvas{}
+	c.Resize(w, h)
+	return &c
+}
+
+// SetCell sets the cell at x, y. Anything outside of the buffer is ignored.
+func (c *BufferCanvas) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return
+	}
+	c.cells[y*c.width+x] = termbox.Cell{Ch: ch, Fg: fg, Bg: bg}
+}
+
+// GetCell returns the cell at x, y
+func (c *BufferCanvas) GetCell(x, y int) termbox.Cell {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return termbox.Cell{}
+	}
+	return c.cells[y*c.width+x]
+}
+
+// Size returns the width and height of the buffer
+func (c *BufferCanvas) Size() (int, int) { return c.width, c.height }
+
+// SubCanvas returns a region of the buffer
+func (c *BufferCanvas) SubCanvas(x, y, w, h int) Canvas {
+	return createSubCanvas(c, x, y, w, h)
+}
+
+// Resize sets the size of the buffer, keeping whatever
+// cells still fit
+func (c *BufferCanvas) Resize(w, h int) {
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	cells := make([]termbox.Cell, w*h)
+	for yy := 0; yy < h && yy < c.height; yy++ {
+		for xx := 0; xx < w && xx < c.width; xx++ {
+			cells[yy*w+xx] = c.cells[yy*c.width+xx]
+		}
+	}
+	c.width, c.height, c.cells = w, h, cells
+}
+
+// Clear resets every cell in the buffer to ch with fg, bg
+func (c *BufferCanvas) Clear(ch rune, fg, bg termbox.Attribute) {
+	for idx := range c.cells {
+		c.cells[idx] = termbox.Cell{Ch: ch, Fg: fg, Bg: bg}
+	}
+}
+
+// GetCells returns a copy of the buffer as rows of cells
+func (c *BufferCanvas) GetCells() [][]termbox.Cell {
+	ret := make([][]termbox.Cell, c.height)
+	for yy := range ret {
+		ret[yy] = make([]termbox.Cell, c.width)
+		copy(ret[yy], c.cells[yy*c.width:(yy+1)*c.width])
+	}
+	return ret
+}
+
+// GetStrings returns the text in the buffer, one string per row.
+// Cells that were never drawn are returned as spaces.
+func (c *BufferCanvas) GetStrings() []string {
+	ret := make([]string, c.height)
+	for yy := range ret {
+		ret[yy] = CellsToString(c.cells[yy*c.width : (yy+1)*c.width])
+	}
+	return ret
+}
+
+// DrawTo copies the buffer onto the canvas cnv at x, y
+func (c *BufferCanvas) DrawTo(cnv Canvas, x, y int) {
+	for yy := 0; yy < c.height; yy++ {
+		for xx := 0; xx < c.width; xx++ {
+			cell := c.cells[yy*c.width+xx]
+			cnv.SetCell(x+xx, y+yy, cell.Ch, cell.Fg, cell.Bg)
+		}
+	}
+}
+
+/* subCanvas */
+
+// subCanvas is a translated, clipped region of another canvas
+type subCanvas struct {
+	parent              Canvas
+	x, y, width, height int
+}
+
+func createSubCanvas(parent Canvas, x, y, w, h int) *subCanvas {
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	return &subCanvas{parent: parent, x: x, y: y, width: w, height: h}
+}
+
+func (c *subCanvas) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return
+	}
+	c.parent.SetCell(c.x+x, c.y+y, ch, fg, bg)
+}
+
+func (c *subCanvas) GetCell(x, y int) termbox.Cell {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return termbox.Cell{}
+	}
+	return c.parent.GetCell(c.x+x, c.y+y)
+}
+
+func (c *subCanvas) Size() (int, int) { return c.width, c.height }
+
+func (c *subCanvas) SubCanvas(x, y, w, h int) Canvas {
+	return createSubCanvas(c, x, y, w, h)
+}
+
+// CopyCanvas returns a BufferCanvas with a copy of everything on cnv
+func CopyCanvas(cnv Canvas) *BufferCanvas {
+	w, h := cnv.Size()
+	buf := CreateBufferCanvas(w, h)
+	for yy := 0; yy < h; yy++ {
+		for xx := 0; xx < w; xx++ {
+			cell := cnv.GetCell(xx, yy)
+			buf.SetCell(xx, yy, cell.Ch, cell.Fg, cell.Bg)
+		}
+	}
+	return buf
+}
+
+// DrawControlToBuffer draws the control t into a new BufferCanvas.
+// The buffer covers the same area that the control fills and borders
+// on the screen, from x, y through x+width, y+height, with the
+// control's x, y at 0, 0.
+func DrawControlToBuffer(t drawable) *BufferCanvas {
+	x, y := t.GetX(), t.GetY()
+	w, h := t.GetWidth()+1, t.GetHeight()+1
+	buf := CreateBufferCanvas(w, h)
+	DrawToCanvas(buf.SubCanvas(-x, -y, x+w, y+h), t)
+	return buf
+}
+
+/* clipCanvas */
+
+// clipCanvas is a region of another canvas that keeps the parent's coordinates
+type clipCanvas struct {
+	parent              Canvas
+	x, y, width, height int
+}
+
+// ClipCanvas returns a canvas that draws to cnv using the same coordinates,
+// but drops anything outside of the area at x, y that is w by h
+func ClipCanvas(cnv Canvas, x, y, w, h int) Canvas {
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	return &clipCanvas{parent: cnv, x: x, y: y, width: w, height: h}
+}
+
+func (c *clipCanvas) contains(x, y int) bool {
+	return x >= c.x && y >= c.y && x < c.x+c.width && y < c.y+c.height
+}
+
+func (c *clipCanvas) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	if c.contains(x, y) {
+		c.parent.SetCell(x, y, ch, fg, bg)
+	}
+}
+
+func (c *clipCanvas) GetCell(x, y int) termbox.Cell {
+	if !c.contains(x, y) {
+		return termbox.Cell{}
+	}
+	return c.parent.GetCell(x, y)
+}
+
+func (c *clipCanvas) Size() (int, int) { return c.parent.Size() }
+
+func (c *clipCanvas) SubCanvas(x, y, w, h int) Canvas {
+	return createSubCanvas(c, x, y, w, h)
+}
@@ -3,115 +3,76 @@ package termboxUtil
 import termbox "github.com/nsf/termbox-go"
 
 type Checkbox struct {
-	id                  string
-	title               string
-	isChecked           bool
-	x, y, width, height int
-	fg, bg              termbox.Attribute
-	activeFg, activeBg  termbox.Attribute
-	bordered            bool
-	tabSkip             bool
-	active              bool
+	BaseControl
+	title     string
+	isChecked bool
+	keymap    *Keymap
+
+	onToggle func(*Checkbox, bool)
 }
 
 func CreateCheckbox(lbl string, x, y, w, h int, fg, bg termbox.Attribute) *Checkbox {
-	c := Checkbox{
-		x: x, y: y, width: w, height: h,
-		fg: fg, bg: bg, activeFg: fg, activeBg: bg,
-	}
+	c := Checkbox{BaseControl: CreateBaseControl(x, y, w, h, fg, bg)}
 	return &c
 }
 
-func (c *Checkbox) SetTitle(title string)                 { c.title = title }
-func (c *Checkbox) SetActiveFgColor(fg termbox.Attribute) { c.activeFg = fg }
-func (c *Checkbox) SetActiveBgColor(bg termbox.Attribute) { c.activeBg = bg }
-func (c *Checkbox) SetActive(a bool)                      { c.active = a }
-func (c *Checkbox) IsActive() bool                        { return c.active }
-
-// GetID returns this control's ID
-func (c *Checkbox) GetID() string { return c.id }
-
-// SetID sets this control's ID
-func (c *Checkbox) SetID(newID string) {
-	c.id = newID
-}
-
-// GetX returns the x position of the input field
-func (c *Checkbox) GetX() int { return c.x }
-
-// SetX sets the x position of the input field
-func (c *Checkbox) SetX(x int) {
-	c.x = x
-}
-
-// GetY returns the y position of the input field
-func (c *Checkbox) GetY() int { return c.y }
-
-// SetY sets the y position of the input field
-func (c *Checkbox) SetY(y int) {
-	c.y = y
-}
-
-// GetWidth returns the current width of the input field
-func (c *Checkbox) GetWidth() int { return c.width }
-
-// SetWidth sets the current width of the input field
-func (c *Checkbox) SetWidth(w int) {
-	c.width = w
-}
+func (c *Checkbox) SetTitle(title string) { c.title, c.dirty = title, true }
 
-// GetHeight returns the current height of the input field
-func (c *Checkbox) GetHeight() int { return c.height }
+// GetKeymap returns the keymap the checkbox uses
+func (c *Checkbox) GetKeymap() *Keymap { return ownKeymap(&c.keymap) }
 
-// SetHeight sets the current height of the input field
-func (c *Checkbox) SetHeight(h int) {
-	c.height = h
+// SetKeymap sets the keymap the checkbox uses, nil uses the DefaultKeymap
+func (c *Checkbox) SetKeymap(k *Keymap) {
+	c.keymap = k
 }
 
-// GetFgColor returns the foreground color
-func (c *Checkbox) GetFgColor() termbox.Attribute { return c.fg }
+// IsChecked returns whether the checkbox is checked
+func (c *Checkbox) IsChecked() bool { return c.isChecked }
 
-// SetFgColor sets the foreground color
-func (c *Checkbox) SetFgColor(fg termbox.Attribute) {
-	c.fg = fg
+// SetChecked sets whether the checkbox is checked
+func (c *Checkbox) SetChecked(b bool) {
+	c.isChecked = b
+	c.dirty = true
 }
 
-// GetBgColor returns the background color
-func (c *Checkbox) GetBgColor() termbox.Attribute { return c.bg }
+// SetOnToggle sets a function that is called with whether the
+// checkbox is checked whenever the user toggles it
+func (c *Checkbox) SetOnToggle(f func(cb *Checkbox, checked bool)) { c.onToggle = f }
 
-// SetBgColor sets the current background color
-func (c *Checkbox) SetBgColor(bg termbox.Attribute) {
-	c.bg = bg
+// toggle checks the checkbox if it isn't checked, or unchecks it if it is
+func (c *Checkbox) toggle() {
+	c.isChecked = !c.isChecked
+	if c.onToggle != nil {
+		c.onToggle(c, c.isChecked)
+	}
 }
 
-// IsBordered returns true or false if this input field has a border
-func (c *Checkbox) IsBordered() bool { return c.bordered }
-
-// SetBordered sets whether we render a border around the input field
-func (c *Checkbox) SetBordered(b bool) {
-	c.bordered = b
+// HandleEvent accepts the termbox event and returns whether it was consumed
+func (c *Checkbox) HandleEvent(event termbox.Event) bool {
+	if c.keymap.Match(event, ActionActivate) == ActionActivate {
+		c.toggle()
+		return true
+	}
+	return false
 }
 
-// IsTabSkipped returns whether this modal has it's tabskip flag set
-func (c *Checkbox) IsTabSkipped() bool {
-	return c.tabSkip
+// HandleMouse toggles the checkbox when it's clicked
+func (c *Checkbox) HandleMouse(ev MouseEvent) bool {
+	if ev.Action == MouseClick || ev.Action == MouseDoubleClick {
+		c.toggle()
+		return true
+	}
+	return false
 }
 
-// SetTabSkip sets the tabskip flag for this control
-func (c *Checkbox) SetTabSkip(b bool) {
-	c.tabSkip = b
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *Checkbox) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
 }
 
-// HandleEvent accepts the termbox event and returns whether it was consumed
-func (c *Checkbox) HandleEvent(event termbox.Event) bool {
-	if event.Ch == 0 {
-		switch event.Key {
-		case termbox.KeySpace, termbox.KeyEnter:
-			c.isChecked = !c.isChecked
-			return true
-		}
-	}
-	return false
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *Checkbox) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
 }
 
 func (c *Checkbox) Draw() {
@@ -121,7 +82,7 @@ func (c *Checkbox) Draw() {
 		useFg, useBg = c.activeFg, c.activeBg
 	}
 	if c.bordered {
-		DrawBorder(c.x, c.y, c.x+c.width, c.y+c.height, useFg, useBg)
+		DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", useFg, useBg)
 		x++
 		w = w - 2
 	}
@@ -135,10 +96,6 @@ func (c *Checkbox) Draw() {
 	w = w - 3
 
 	if c.title != "" {
-		wrk := c.title
-		if len(wrk) > w {
-			wrk = wrk[:w]
-		}
-		DrawStringAtPoint(wrk, x, y, useFg, useBg)
+		DrawStringAtPoint(TruncateText(c.title, w), x, y, useFg, useBg)
 	}
 }
@@ -6,25 +6,29 @@ import (
 
 // ConfirmModal is a modal with yes/no (or similar) buttons
 type ConfirmModal struct {
-	id                  string
-	title               string
-	text                string
-	x, y, width, height int
-	showHelp            bool
-	cursor              int
-	fg, bg              termbox.Attribute
-	activeFg, activeBg  termbox.Attribute
-	isDone              bool
-	accepted            bool
-	value               string
-	isVisible           bool
-	bordered            bool
-	tabSkip             bool
-}
-
-// CreateConfirmModal Creates a confirmation modal with the specified attributes
+	BaseControl
+	title      string
+	text       string
+	textFormat TextFormat
+	showHelp   bool
+	cursor     int
+	isDone     bool
+	accepted   bool
+	value      string
+	isVisible  bool
+	theme      *Theme
+	keymap     *Keymap
+
+	onSubmit func(*ConfirmModal)
+	onCancel func(*ConfirmModal)
+}
+
+// CreateConfirmModal Creates a confirmation modal with the specified attributes.
+// x, y, width and height are only where it starts out, push it with
+// ModalStack.PushPlaced to have it placed on the screen as that's resized.
 func CreateConfirmModal(title string, x, y, width, height int, fg, bg termbox.Attribute) *ConfirmModal {
-	i := ConfirmModal{title: title, x: x, y: y, width: width, height: height, fg: fg, bg: bg, activeFg: fg, activeBg: bg}
+	i := ConfirmModal{BaseControl: CreateBaseControl(x, y, width, height, fg, bg), title: title}
+	i.bordered = true
 	if i.title == "" && i.text == "" {
 		i.title = "Confirm?"
 	}
@@ -32,20 +36,13 @@ func CreateConfirmModal(title string, x, y, width, height int, fg, bg termbox.At
 	return &i
 }
 
-// GetID returns this control's ID
-func (i *ConfirmModal) GetID() string { return i.id }
-
-// SetID sets this control's ID
-func (i *ConfirmModal) SetID(newID string) {
-	i.id = newID
-}
-
 // GetTitle returns the current title of the modal
 func (i *ConfirmModal) GetTitle() string { return i.title }
 
 // SetTitle sets the current title of the modal to s
 func (i *ConfirmModal) SetTitle(s string) {
 	i.title = s
+	i.dirty = true
 }
 
 // GetText returns the current text of the modal
@@ -54,38 +51,17 @@ func (i *ConfirmModal) GetText() string { return i.text }
 // SetText sets the text of the modal to s
 func (i *ConfirmModal) SetText(s string) {
 	i.text = s
+	i.dirty = true
 }
 
-// GetX returns the current x coordinate of the modal
-func (i *ConfirmModal) GetX() int { return i.x }
-
-// SetX sets the current x coordinate of the modal to x
-func (i *ConfirmModal) SetX(x int) {
-	i.x = x
-}
-
-// GetY returns the current y coordinate of the modal
-func (i *ConfirmModal) GetY() int { return i.y }
+// GetTextFormat returns how the modal's text is interpreted
+func (i *ConfirmModal) GetTextFormat() TextFormat { return i.textFormat }
 
-// SetY sets the current y coordinate of the modal to y
-func (i *ConfirmModal) SetY(y int) {
-	i.y = y
-}
-
-// GetWidth returns the current width of the modal
-func (i *ConfirmModal) GetWidth() int { return i.width }
-
-// SetWidth sets the current modal width to width
-func (i *ConfirmModal) SetWidth(width int) {
-	i.width = width
-}
-
-// GetHeight returns the current height of the modal
-func (i *ConfirmModal) GetHeight() int { return i.height }
-
-// SetHeight set the height of the modal to height
-func (i *ConfirmModal) SetHeight(height int) {
-	i.height = height
+// SetTextFormat sets how the modal's text is interpreted,
+// TextMarkup allows inline style tags (see ParseStyledText)
+func (i *ConfirmModal) SetTextFormat(f TextFormat) {
+	i.textFormat = f
+	i.dirty = true
 }
 
 // HelpIsShown returns true or false if the help is displayed
@@ -94,22 +70,7 @@ func (i *ConfirmModal) HelpIsShown() bool { return i.showHelp }
 // ShowHelp sets whether or not to display the help text
 func (i *ConfirmModal) ShowHelp(b bool) {
 	i.showHelp = b
-}
-
-// GetFgColor returns the foreground color
-func (i *ConfirmModal) GetFgColor() termbox.Attribute { return i.fg }
-
-// SetFgColor sets the foreground color
-func (i *ConfirmModal) SetFgColor(fg termbox.Attribute) {
-	i.fg = fg
-}
-
-// GetBgColor returns the background color
-func (i *ConfirmModal) GetBgColor() termbox.Attribute { return i.bg }
-
-// SetBgColor sets the current background color
-func (i *ConfirmModal) SetBgColor(bg termbox.Attribute) {
-	i.bg = bg
+	i.dirty = true
 }
 
 // IsDone returns whether the user has answered the modal
@@ -118,85 +79,114 @@ func (i *ConfirmModal) IsDone() bool { return i.isDone }
 // SetDone sets whether the modal has completed it's purpose
 func (i *ConfirmModal) SetDone(b bool) {
 	i.isDone = b
+	i.dirty = true
 }
 
 // Show sets the visibility flag of the modal to true
 func (i *ConfirmModal) Show() {
 	i.isVisible = true
+	i.dirty = true
 }
 
 // Hide sets the visibility flag of the modal to false
 func (i *ConfirmModal) Hide() {
 	i.isVisible = false
+	i.dirty = true
 }
 
 // IsAccepted returns whether the user accepted the modal
 func (i *ConfirmModal) IsAccepted() bool { return i.accepted }
 
+// SetOnSubmit sets a function that is called when the user answers yes
+func (i *ConfirmModal) SetOnSubmit(f func(m *ConfirmModal)) { i.onSubmit = f }
+
+// SetOnCancel sets a function that is called when the user answers no
+func (i *ConfirmModal) SetOnCancel(f func(m *ConfirmModal)) { i.onCancel = f }
+
 // Clear clears all of the non-positional parameters of the modal
 func (i *ConfirmModal) Clear() {
 	i.title = ""
 	i.text = ""
 	i.accepted = false
 	i.isDone = false
+	i.dirty = true
 }
 
-// IsBordered returns whether this modal is bordered or not
-func (i *ConfirmModal) IsBordered() bool {
-	return i.bordered
-}
-
-// SetBordered sets whether we render a border around the frame
-func (i *ConfirmModal) SetBordered(b bool) {
-	i.bordered = b
-}
+// GetKeymap returns the keymap the modal uses
+func (i *ConfirmModal) GetKeymap() *Keymap { return ownKeymap(&i.keymap) }
 
-// IsTabSkipped returns whether this modal has it's tabskip flag set
-func (i *ConfirmModal) IsTabSkipped() bool {
-	return i.tabSkip
+// SetKeymap sets the keymap the modal uses, nil uses the DefaultKeymap
+func (i *ConfirmModal) SetKeymap(k *Keymap) {
+	i.keymap = k
 }
 
-// SetTabSkip sets the tabskip flag for this control
-func (i *ConfirmModal) SetTabSkip(b bool) {
-	i.tabSkip = b
+// ApplyTheme sets the colors of the control from the theme t
+func (i *ConfirmModal) ApplyTheme(t *Theme) {
+	i.fg, i.bg = t.Base.Fg, t.Base.Bg
+	i.activeFg, i.activeBg = t.Active.Fg, t.Active.Bg
+	i.borderStyle = t.BorderStyle
+	i.theme = t
 }
 
 // HandleEvent handles the termbox event and returns whether it was consumed
 func (i *ConfirmModal) HandleEvent(event termbox.Event) bool {
-	if event.Ch == 'Y' || event.Ch == 'y' {
+	switch i.keymap.Match(event, ActionYes, ActionNo) {
+	case ActionYes:
 		i.accepted = true
 		i.isDone = true
+		if i.onSubmit != nil {
+			i.onSubmit(i)
+		}
 		return true
-	} else if event.Ch == 'N' || event.Ch == 'n' {
+	case ActionNo:
 		i.accepted = false
 		i.isDone = true
+		if i.onCancel != nil {
+			i.onCancel(i)
+		}
 		return true
 	}
 	return false
 }
 
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (i *ConfirmModal) DrawToStrings() []string {
+	return DrawControlToBuffer(i).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (i *ConfirmModal) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(i).GetCells()
+}
+
 // Draw draws the modal
 func (i *ConfirmModal) Draw() {
 	// First blank out the area we'll be putting the modal
 	FillWithChar(' ', i.x, i.y, i.x+i.width, i.y+i.height, i.fg, i.bg)
 	// Now draw the border
-	DrawBorder(i.x, i.y, i.x+i.width, i.y+i.height, i.fg, i.bg)
+	DrawStyledBorder(i.x, i.y, i.x+i.width, i.y+i.height, i.borderStyle, "", i.fg, i.bg)
+
+	titleFg, titleBg, helpFg, helpBg := i.fg, i.bg, i.fg, i.bg
+	if i.theme != nil {
+		titleFg, titleBg = i.theme.Title.Fg, i.theme.Title.Bg
+		helpFg, helpBg = i.theme.Help.Fg, i.theme.Help.Bg
+	}
 
 	nextY := i.y + 1
 	// The title
 	if i.title != "" {
-		DrawStringAtPoint(i.title, i.x+1, nextY, i.fg, i.bg)
+		DrawStringAtPoint(i.title, i.x+1, nextY, titleFg, titleBg)
 		nextY++
 		FillWithChar('-', i.x+1, nextY, i.x+i.width-1, nextY, i.fg, i.bg)
 		nextY++
 	}
 	if i.text != "" {
-		DrawStringAtPoint(i.text, i.x+1, nextY, i.fg, i.bg)
+		DrawFormattedStringAtPoint(i.text, i.textFormat, i.x+1, nextY, i.fg, i.bg)
 	}
 	nextY += 2
 	if i.showHelp {
 		helpString := " (Y/y) Confirm. (N/n) Reject. "
-		helpX := (i.x + i.width) - len(helpString) - 1
-		DrawStringAtPoint(helpString, helpX, nextY, i.fg, i.bg)
+		helpX := (i.x + i.width) - TextWidth(helpString) - 1
+		DrawStringAtPoint(helpString, helpX, nextY, helpFg, helpBg)
 	}
 }
@@ -0,0 +1,195 @@
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+// BaseControl is the plumbing every control needs: an ID, position and
+// size, colors, a border, tab skipping and whether it's active. Embed it
+// in a control and add HandleEvent and Draw to make a Control:
+//
+//	type Clock struct {
+//		termboxUtil.BaseControl
+//	}
+//
+//	func CreateClock(x, y int, fg, bg termbox.Attribute) *Clock {
+//		return &Clock{BaseControl: termboxUtil.CreateBaseControl(x, y, 8, 1, fg, bg)}
+//	}
+//
+//	func (c *Clock) HandleEvent(event termbox.Event) bool { return false }
+//
+//	func (c *Clock) Draw() {
+//		fg, bg := c.GetDrawColors()
+//		termboxUtil.DrawStringAtPoint(time.Now().Format("15:04:05"), c.GetX(), c.GetY(), fg, bg)
+//	}
+//
+// Any of the methods can be overridden by the control embedding it.
+// Anything that changes what the control draws should call MarkDirty,
+// so an App tracking damage knows to redraw it.
+type BaseControl struct {
+	id                  string
+	x, y, width, height int
+	fg, bg              termbox.Attribute
+	activeFg, activeBg  termbox.Attribute
+	bordered            bool
+	borderStyle         BorderStyle
+	tabSkip             bool
+	active              bool
+	dirty               bool
+}
+
+// CreateBaseControl returns a BaseControl at x, y that is w by h, to be
+// embedded in a control. The active colors start out the same as fg and bg.
+func CreateBaseControl(x, y, w, h int, fg, bg termbox.Attribute) BaseControl {
+	return BaseControl{x: x, y: y, width: w, height: h,
+		fg: fg, bg: bg, activeFg: fg, activeBg: bg,
+		dirty: true,
+	}
+}
+
+// GetID returns this control's ID
+func (c *BaseControl) GetID() string { return c.id }
+
+// SetID sets this control's ID
+func (c *BaseControl) SetID(newID string) { c.id = newID }
+
+// GetX returns the x position of the control
+func (c *BaseControl) GetX() int { return c.x }
+
+// SetX sets the x position of the control
+func (c *BaseControl) SetX(x int) {
+	if c.x != x {
+		c.x, c.dirty = x, true
+	}
+}
+
+// GetY returns the y position of the control
+func (c *BaseControl) GetY() int { return c.y }
+
+// SetY sets the y position of the control
+func (c *BaseControl) SetY(y int) {
+	if c.y != y {
+		c.y, c.dirty = y, true
+	}
+}
+
+// GetWidth returns the width of the control
+func (c *BaseControl) GetWidth() int { return c.width }
+
+// SetWidth sets the width of the control
+func (c *BaseControl) SetWidth(w int) {
+	if c.width != w {
+		c.width, c.dirty = w, true
+	}
+}
+
+// GetHeight returns the height of the control
+func (c *BaseControl) GetHeight() int { return c.height }
+
+// SetHeight sets the height of the control
+func (c *BaseControl) SetHeight(h int) {
+	if c.height != h {
+		c.height, c.dirty = h, true
+	}
+}
+
+// GetFgColor returns the foreground color
+func (c *BaseControl) GetFgColor() termbox.Attribute { return c.fg }
+
+// SetFgColor sets the foreground color
+func (c *BaseControl) SetFgColor(fg termbox.Attribute) {
+	if c.fg != fg {
+		c.fg, c.dirty = fg, true
+	}
+}
+
+// GetBgColor returns the background color
+func (c *BaseControl) GetBgColor() termbox.Attribute { return c.bg }
+
+// SetBgColor sets the background color
+func (c *BaseControl) SetBgColor(bg termbox.Attribute) {
+	if c.bg != bg {
+		c.bg, c.dirty = bg, true
+	}
+}
+
+// GetActiveFgColor returns the foreground color used when the control is active
+func (c *BaseControl) GetActiveFgColor() termbox.Attribute { return c.activeFg }
+
+// SetActiveFgColor sets the foreground color used when the control is active
+func (c *BaseControl) SetActiveFgColor(fg termbox.Attribute) {
+	if c.activeFg != fg {
+		c.activeFg, c.dirty = fg, true
+	}
+}
+
+// GetActiveBgColor returns the background color used when the control is active
+func (c *BaseControl) GetActiveBgColor() termbox.Attribute { return c.activeBg }
+
+// SetActiveBgColor sets the background color used when the control is active
+func (c *BaseControl) SetActiveBgColor(bg termbox.Attribute) {
+	if c.activeBg != bg {
+		c.activeBg, c.dirty = bg, true
+	}
+}
+
+// GetDrawColors returns the active colors if the control is active,
+// otherwise the foreground and background colors
+func (c *BaseControl) GetDrawColors() (termbox.Attribute, termbox.Attribute) {
+	if c.active {
+		return c.activeFg, c.activeBg
+	}
+	return c.fg, c.bg
+}
+
+// IsBordered returns whether the control has a border
+func (c *BaseControl) IsBordered() bool { return c.bordered }
+
+// SetBordered sets whether the control has a border
+func (c *BaseControl) SetBordered(b bool) {
+	if c.bordered != b {
+		c.bordered, c.dirty = b, true
+	}
+}
+
+// GetBorderStyle returns the style the border is drawn in
+func (c *BaseControl) GetBorderStyle() BorderStyle { return c.borderStyle }
+
+// SetBorderStyle sets the style the border is drawn in
+func (c *BaseControl) SetBorderStyle(s BorderStyle) {
+	c.borderStyle = s
+	c.dirty = true
+}
+
+// IsTabSkipped returns whether the control is skipped when tabbing
+func (c *BaseControl) IsTabSkipped() bool { return c.tabSkip }
+
+// SetTabSkip sets whether the control is skipped when tabbing
+func (c *BaseControl) SetTabSkip(b bool) { c.tabSkip = b }
+
+// IsActive returns whether the control is active
+func (c *BaseControl) IsActive() bool { return c.active }
+
+// SetActive sets whether the control is active
+func (c *BaseControl) SetActive(a bool) {
+	if c.active != a {
+		c.active, c.dirty = a, true
+	}
+}
+
+// ApplyTheme sets the colors and border style of the control from the theme t
+func (c *BaseControl) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
+	c.borderStyle = t.BorderStyle
+	c.dirty = true
+}
+
+// IsDirty returns whether the control has changed since it was last drawn
+func (c *BaseControl) IsDirty() bool { return c.dirty }
+
+// MarkDirty tells an App tracking damage that the control has changed and
+// needs to be redrawn. The setters in BaseControl do this, a control
+// embedding it should do it whenever anything else it draws changes.
+func (c *BaseControl) MarkDirty() { c.dirty = true }
+
+// ClearDirty marks the control as drawn
+func (c *BaseControl) ClearDirty() { c.dirty = false }
@@ -0,0 +1,53 @@
+package termboxUtil_test
+
+import (
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+// counter is a custom control built on BaseControl, it counts the
+// '+' keys it gets
+type counter struct {
+	termboxUtil.BaseControl
+	n int
+}
+
+func (c *counter) HandleEvent(ev termbox.Event) bool {
+	if ev.Ch != '+' {
+		return false
+	}
+	c.n++
+	return true
+}
+
+func (c *counter) Draw() {
+	fg, bg := c.GetDrawColors()
+	termboxUtil.DrawStringAtPoint(string(rune('0'+c.n)), c.GetX(), c.GetY(), fg, bg)
+}
+
+func TestCustomControlInFrame(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	cnt := &counter{BaseControl: termboxUtil.CreateBaseControl(3, 1, 1, 1, fg, bg)}
+	cnt.SetID("count")
+	cnt.SetActiveFgColor(termbox.ColorGreen)
+	var _ termboxUtil.Control = cnt
+
+	frm := termboxUtil.CreateFrame(0, 0, 10, 3, fg, bg)
+	frm.AddControl(termboxUtil.CreateLabel("n:", 1, 1, 2, 1, fg, bg))
+	frm.AddControl(cnt)
+	frm.SetActive(true)
+	if termboxUtil.FindByID(frm, "count") != cnt {
+		t.Fatal("expected to find the custom control by its ID")
+	}
+	s := screentest.CreateScreen(11, 4)
+	s.Send(frm, screentest.Runes("++-+")...)
+	s.Draw(frm)
+	if cnt.n != 3 {
+		t.Errorf("expected the custom control to get the 3 '+' keys, got %d", cnt.n)
+	}
+	// It's drawn where it was put in the frame, in its active colors
+	s.AssertCell(t, 3, 1, '3', termbox.ColorGreen, bg)
+}
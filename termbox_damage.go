@@ -0,0 +1,385 @@
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+// dirtyControl is a control that knows when it needs to be redrawn.
+// Controls that aren't are redrawn every time an App tracking damage
+// redraws anything.
+type dirtyControl interface {
+	IsDirty() bool
+	MarkDirty()
+	ClearDirty()
+}
+
+// damageContainer is a control that draws other controls without being
+// a containerControl (like a ModalStack), so damage can still be tracked
+// for them and they can still be ticked
+type damageContainer interface {
+	damageControls() []Control
+}
+
+// markDirty marks t as needing to be redrawn, if it keeps track of that
+func markDirty(t Control) {
+	if v, ok := t.(dirtyControl); ok {
+		v.MarkDirty()
+	}
+}
+
+// markLeafDirty marks t as needing to be redrawn after it has handled an
+// event, unless it's a container. A container passes events on to its
+// controls, which mark themselves, and marking it would redraw all of it.
+func markLeafDirty(t Control) {
+	if _, ok := t.(containerControl); ok {
+		return
+	}
+	if _, ok := t.(damageContainer); ok {
+		return
+	}
+	markDirty(t)
+}
+
+// isDirty returns whether t needs to be redrawn
+func isDirty(t Control) bool {
+	if v, ok := t.(dirtyControl); ok {
+		return v.IsDirty()
+	}
+	return true
+}
+
+// walkDamage calls fn for root and every control under it, including
+// the controls in damage containers
+func walkDamage(root Control, fn func(t Control)) {
+	if root == nil {
+		return
+	}
+	fn(root)
+	var ctls []Control
+	if v, ok := root.(containerControl); ok {
+		ctls = v.GetControls()
+	} else if v, ok := root.(damageContainer); ok {
+		ctls = v.damageControls()
+	}
+	for _, t := range ctls {
+		walkDamage(t, fn)
+	}
+}
+
+/* damageRect */
+
+// damageRect is an area of the screen
+type damageRect struct {
+	x, y, w, h int
+}
+
+func (r damageRect) empty() bool { return r.w <= 0 || r.h <= 0 }
+
+func (r damageRect) intersects(o damageRect) bool {
+	return !r.empty() && !o.empty() &&
+		r.x < o.x+o.w && o.x < r.x+r.w && r.y < o.y+o.h && o.y < r.y+r.h
+}
+
+func (r damageRect) contains(o damageRect) bool {
+	return o.empty() || (o.x >= r.x && o.y >= r.y && o.x+o.w <= r.x+r.w && o.y+o.h <= r.y+r.h)
+}
+
+func (r damageRect) intersect(o damageRect) damageRect {
+	x1, y1 := maxInt(r.x, o.x), maxInt(r.y, o.y)
+	x2, y2 := minInt(r.x+r.w, o.x+o.w), minInt(r.y+r.h, o.y+o.h)
+	if x2 <= x1 || y2 <= y1 {
+		return damageRect{}
+	}
+	return damageRect{x1, y1, x2 - x1, y2 - y1}
+}
+
+// extend grows r to take in the cell at x, y
+func (r damageRect) extend(x, y int) damageRect {
+	if r.empty() {
+		return damageRect{x, y, 1, 1}
+	}
+	x1, y1 := minInt(r.x, x), minInt(r.y, y)
+	x2, y2 := maxInt(r.x+r.w, x+1), maxInt(r.y+r.h, y+1)
+	return damageRect{x1, y1, x2 - x1, y2 - y1}
+}
+
+// union returns the smallest area that takes in r and o
+func (r damageRect) union(o damageRect) damageRect {
+	if r.empty() {
+		return o
+	}
+	if o.empty() {
+		return r
+	}
+	x1, y1 := minInt(r.x, o.x), minInt(r.y, o.y)
+	x2, y2 := maxInt(r.x+r.w, o.x+o.w), maxInt(r.y+r.h, o.y+o.h)
+	return damageRect{x1, y1, x2 - x1, y2 - y1}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+/* damageTracker */
+
+// damageTracker remembers where each control was drawn, so that when some
+// of them change only the areas they cover need to be redrawn
+type damageTracker struct {
+	drawn map[Control]damageRect
+	// full is set when everything has to be redrawn
+	full bool
+
+	// During a redraw: the areas being redrawn (nil for everything)
+	// and the controls that were dirty when it started
+	rects []damageRect
+	dirty map[Control]bool
+}
+
+// currentDamage is the tracker for the redraw going on, if there is one
+var currentDamage *damageTracker
+
+func createDamageTracker() *damageTracker {
+	return &damageTracker{drawn: make(map[Control]damageRect), full: true}
+}
+
+// draw redraws whatever has changed under root on the current canvas
+// since the last time, clearing it to fg, bg first. Everything is
+// redrawn if it has to be.
+func (d *damageTracker) draw(root Control, fg, bg termbox.Attribute) {
+	if root == nil {
+		clearCanvas(GetCanvas(), nil, fg, bg)
+		return
+	}
+	var rects []damageRect
+	var keys []Control
+	full := d.full
+	if !full {
+		rects, keys, full = d.collect(root)
+	}
+	switch {
+	case full:
+		d.pass(root, nil, fg, bg)
+	case len(rects) > 0:
+		d.pass(root, rects, fg, bg)
+		// Anything that grew past where it was drawn last time
+		// needs what's under the new area redrawn too
+		var extra []damageRect
+		for _, k := range keys {
+			if r := d.drawn[k]; !coveredBy(r, rects) {
+				extra = append(extra, r)
+			}
+		}
+		if len(extra) > 0 {
+			d.pass(root, extra, fg, bg)
+		}
+	}
+	d.full = false
+	d.dirty = nil
+	walkDamage(root, func(t Control) {
+		if v, ok := t.(dirtyControl); ok {
+			v.ClearDirty()
+		}
+	})
+}
+
+// collect finds the areas that need to be redrawn: everywhere a dirty
+// control was drawn last time, or its closest drawn container if it
+// hasn't been drawn on its own. keys are the controls those areas
+// belong to. full is true if everything needs to be redrawn.
+func (d *damageTracker) collect(root Control) (rects []damageRect, keys []Control, full bool) {
+	d.dirty = make(map[Control]bool)
+	seen := make(map[Control]bool)
+	var walk func(t, key Control)
+	walk = func(t, key Control) {
+		seen[t] = true
+		if r, ok := d.drawn[t]; ok && !r.empty() {
+			key = t
+		}
+		if isDirty(t) {
+			d.dirty[t] = true
+			if key == nil {
+				full = true
+			} else {
+				rects = append(rects, d.drawn[key])
+				keys = append(keys, key)
+			}
+		}
+		var ctls []Control
+		if v, ok := t.(containerControl); ok {
+			ctls = v.GetControls()
+		} else if v, ok := t.(damageContainer); ok {
+			ctls = v.damageControls()
+		}
+		for _, c := range ctls {
+			walk(c, key)
+		}
+	}
+	walk(root, nil)
+	// Forget about controls that aren't around anymore
+	for k := range d.drawn {
+		if !seen[k] {
+			delete(d.drawn, k)
+		}
+	}
+	return rects, keys, full
+}
+
+// pass draws root, only changing the cells in rects (or everywhere if
+// rects is nil), after clearing them to fg, bg
+func (d *damageTracker) pass(root Control, rects []damageRect, fg, bg termbox.Attribute) {
+	d.rects = rects
+	prev := GetCanvas()
+	clearCanvas(prev, rects, fg, bg)
+	if rects != nil {
+		SetCanvas(&damageCanvas{parent: prev, rects: rects})
+	}
+	currentDamage = d
+	w, h := prev.Size()
+	d.drawClipped(root, 0, 0, w, h)
+	currentDamage = nil
+	SetCanvas(prev)
+	d.rects = nil
+}
+
+// drawClipped is DrawClipped while a redraw is going on. It skips
+// controls that haven't changed and are nowhere near the areas being
+// redrawn, and remembers where everything else was drawn.
+func (d *damageTracker) drawClipped(t drawable, x, y, w, h int) {
+	ctl, isCtl := t.(Control)
+	if old, ok := d.drawn[ctl]; isCtl && ok && d.rects != nil && !d.dirty[ctl] && !intersectsAny(old, d.rects) {
+		// Whatever it's drawn in still covers the area it was drawn in
+		if rc := enclosingRecord(currentCanvas); rc != nil {
+			rc.bounds = rc.bounds.union(old)
+		}
+		return
+	}
+	ox, oy := canvasOrigin(currentCanvas)
+	rc := &recordCanvas{Canvas: ClipCanvas(currentCanvas, x, y, w, h), ox: ox, oy: oy}
+	prev := SetCanvas(rc)
+	t.Draw()
+	SetCanvas(prev)
+	if isCtl {
+		d.drawn[ctl] = rc.bounds.intersect(damageRect{x + ox, y + oy, w, h})
+	}
+}
+
+// clearCanvas clears the areas rects (or all of it if rects is nil) on cnv to fg, bg
+func clearCanvas(cnv Canvas, rects []damageRect, fg, bg termbox.Attribute) {
+	if rects == nil {
+		w, h := cnv.Size()
+		rects = []damageRect{{0, 0, w, h}}
+	}
+	for _, r := range rects {
+		for yy := r.y; yy < r.y+r.h; yy++ {
+			for xx := r.x; xx < r.x+r.w; xx++ {
+				cnv.SetCell(xx, yy, ' ', fg, bg)
+			}
+		}
+	}
+}
+
+func intersectsAny(r damageRect, rects []damageRect) bool {
+	for _, o := range rects {
+		if r.intersects(o) {
+			return true
+		}
+	}
+	return false
+}
+
+func coveredBy(r damageRect, rects []damageRect) bool {
+	for _, o := range rects {
+		if o.contains(r) {
+			return true
+		}
+	}
+	return r.empty()
+}
+
+// enclosingRecord returns the record canvas that c draws through, or nil
+func enclosingRecord(c Canvas) *recordCanvas {
+	for c != nil {
+		switch v := c.(type) {
+		case *recordCanvas:
+			return v
+		case *subCanvas:
+			c = v.parent
+		case *clipCanvas:
+			c = v.parent
+		case *damageCanvas:
+			c = v.parent
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// canvasOrigin returns where 0, 0 on c is on the canvas under all of
+// the sub canvases and clipping
+func canvasOrigin(c Canvas) (int, int) {
+	switch v := c.(type) {
+	case *subCanvas:
+		x, y := canvasOrigin(v.parent)
+		return x + v.x, y + v.y
+	case *clipCanvas:
+		return canvasOrigin(v.parent)
+	case *recordCanvas:
+		return canvasOrigin(v.Canvas)
+	case *damageCanvas:
+		return canvasOrigin(v.parent)
+	}
+	return 0, 0
+}
+
+/* recordCanvas */
+
+// recordCanvas is a canvas that keeps track of the area drawn on it,
+// in the coordinates of the canvas under all of the sub canvases
+type recordCanvas struct {
+	Canvas
+	ox, oy int
+	bounds damageRect
+}
+
+func (c *recordCanvas) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	c.bounds = c.bounds.extend(c.ox+x, c.oy+y)
+	c.Canvas.SetCell(x, y, ch, fg, bg)
+}
+
+func (c *recordCanvas) SubCanvas(x, y, w, h int) Canvas {
+	return createSubCanvas(c, x, y, w, h)
+}
+
+/* damageCanvas */
+
+// damageCanvas is a canvas that only changes the cells in the areas being redrawn
+type damageCanvas struct {
+	parent Canvas
+	rects  []damageRect
+}
+
+func (c *damageCanvas) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	for _, r := range c.rects {
+		if x >= r.x && y >= r.y && x < r.x+r.w && y < r.y+r.h {
+			c.parent.SetCell(x, y, ch, fg, bg)
+			return
+		}
+	}
+}
+
+func (c *damageCanvas) GetCell(x, y int) termbox.Cell { return c.parent.GetCell(x, y) }
+
+func (c *damageCanvas) Size() (int, int) { return c.parent.Size() }
+
+func (c *damageCanvas) SubCanvas(x, y, w, h int) Canvas {
+	return createSubCanvas(c, x, y, w, h)
+}
@@ -0,0 +1,37 @@
+package termboxUtil
+
+import (
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestDamageRedrawsOnlyDirtyControls(t *testing.T) {
+	cnv := CreateBufferCanvas(20, 4)
+	defer SetCanvas(SetCanvas(cnv))
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	frm := CreateFrame(0, 0, 19, 3, fg, bg)
+	one := CreateInputField(1, 1, 8, 1, fg, bg)
+	two := CreateInputField(10, 1, 8, 1, fg, bg)
+	frm.AddControl(one)
+	frm.AddControl(two)
+	d := createDamageTracker()
+	d.draw(frm, fg, bg)
+
+	// Anything that isn't redrawn keeps what's on the canvas
+	cnv.SetCell(10, 1, '#', fg, bg)
+	one.SetValue("abc")
+	d.draw(frm, fg, bg)
+	if got := CellsToString(cnv.GetCells()[1][1:4]); got != "abc" {
+		t.Errorf("expected the changed field to be redrawn, got %q", got)
+	}
+	if cnv.GetCell(10, 1).Ch != '#' {
+		t.Error("expected the field that didn't change not to be redrawn")
+	}
+
+	ApplyTheme(two, CreateTheme("red", termbox.ColorRed, termbox.ColorBlack))
+	d.draw(frm, fg, bg)
+	if c := cnv.GetCell(10, 1); c.Ch != ' ' || c.Fg != termbox.ColorRed {
+		t.Errorf("expected the themed field to be redrawn in red, got %+v", c)
+	}
+}
@@ -4,51 +4,31 @@ import "github.com/nsf/termbox-go"
 
 // DropMenu is a title that, when active drops a menu down
 type DropMenu struct {
-	id                  string
-	title               string
-	x, y, width, height int
-	bg, fg              termbox.Attribute
-	activeFg, activeBg  termbox.Attribute
-	cursorBg, cursorFg  termbox.Attribute
-	menu                *Menu
-	menuSelected        bool
-	showMenu            bool
-	bordered            bool
-	tabSkip             bool
-	active              bool
+	BaseControl
+	title              string
+	cursorBg, cursorFg termbox.Attribute
+	menu               *Menu
+	menuSelected       bool
+	showMenu           bool
 }
 
 // CreateDropMenu Creates a menu with the specified attributes
 func CreateDropMenu(title string, options []string, x, y, width, height int, fg, bg, cursorFg, cursorBg termbox.Attribute) *DropMenu {
-	c := DropMenu{
-		title: title,
-		x:     x, y: y, width: width, height: height,
-		fg: fg, bg: bg, activeFg: fg, activeBg: bg,
+	c := DropMenu{BaseControl: CreateBaseControl(x, y, width, height, fg, bg),
+		title:    title,
 		cursorFg: fg, cursorBg: bg,
 	}
 	c.menu = CreateMenu("", options, x, y+2, width, height, fg, bg)
 	return &c
 }
 
-// GetID returns this control's ID
-func (c *DropMenu) GetID() string { return c.id }
-
-// SetID sets this control's ID
-func (c *DropMenu) SetID(newID string) {
-	c.id = newID
-}
-
-func (c *DropMenu) SetActiveFgColor(fg termbox.Attribute) { c.activeFg = fg }
-func (c *DropMenu) SetActiveBgColor(bg termbox.Attribute) { c.activeBg = bg }
-func (c *DropMenu) SetActive(a bool)                      { c.active = a }
-func (c *DropMenu) IsActive() bool                        { return c.active }
-
 // GetTitle returns the current title of the menu
 func (c *DropMenu) GetTitle() string { return c.title }
 
 // SetTitle sets the current title of the menu to s
 func (c *DropMenu) SetTitle(s string) {
 	c.title = s
+	c.dirty = true
 }
 
 // GetMenu returns the menu for this dropmenu
@@ -56,61 +36,32 @@ func (c *DropMenu) GetMenu() *Menu {
 	return c.menu
 }
 
-// GetX returns the current x coordinate of the menu
-func (c *DropMenu) GetX() int { return c.x }
-
 // SetX sets the current x coordinate of the menu to x
 func (c *DropMenu) SetX(x int) {
+	c.menu.SetX(c.menu.GetX() + x - c.x)
 	c.x = x
+	c.dirty = true
 }
 
-// GetY returns the current y coordinate of the menu
-func (c *DropMenu) GetY() int { return c.y }
-
 // SetY sets the current y coordinate of the menu to y
 func (c *DropMenu) SetY(y int) {
+	c.menu.SetY(c.menu.GetY() + y - c.y)
 	c.y = y
+	c.dirty = true
 }
 
-// GetWidth returns the current width of the menu
-func (c *DropMenu) GetWidth() int { return c.width }
-
-// SetWidth sets the current menu width to width
-func (c *DropMenu) SetWidth(width int) {
-	c.width = width
-}
-
-// GetHeight returns the current height of the menu
-func (c *DropMenu) GetHeight() int { return c.height }
-
-// SetHeight set the height of the menu to height
-func (c *DropMenu) SetHeight(height int) {
-	c.height = height
-}
-
-// GetFgColor returns the foreground color
-func (c *DropMenu) GetFgColor() termbox.Attribute { return c.fg }
-
-// SetFgColor sets the foreground color
-func (c *DropMenu) SetFgColor(fg termbox.Attribute) {
-	c.fg = fg
-}
-
-// GetBgColor returns the background color
-func (c *DropMenu) GetBgColor() termbox.Attribute { return c.bg }
-
-// SetBgColor sets the current background color
-func (c *DropMenu) SetBgColor(bg termbox.Attribute) {
-	c.bg = bg
-}
-
-// IsBordered returns the bordered flag
-func (c *DropMenu) IsBordered() bool { return c.bordered }
-
 // SetBordered sets the bordered flag
 func (c *DropMenu) SetBordered(b bool) {
 	c.bordered = b
 	c.menu.SetBordered(b)
+	c.dirty = true
+}
+
+// SetBorderStyle sets the style the border is drawn in
+func (c *DropMenu) SetBorderStyle(s BorderStyle) {
+	c.borderStyle = s
+	c.menu.SetBorderStyle(s)
+	c.dirty = true
 }
 
 // IsDone returns whether the user has answered the modal
@@ -119,34 +70,56 @@ func (c *DropMenu) IsDone() bool { return c.menu.isDone }
 // SetDone sets whether the modal has completed it's purpose
 func (c *DropMenu) SetDone(b bool) {
 	c.menu.isDone = b
+	c.dirty = true
 }
 
-// IsTabSkipped returns whether this modal has it's tabskip flag set
-func (c *DropMenu) IsTabSkipped() bool {
-	return c.tabSkip
+// SetOnSelect sets a function that is called with the option the user chooses
+func (c *DropMenu) SetOnSelect(f func(d *DropMenu, o *MenuOption)) {
+	if f == nil {
+		c.menu.SetOnSelect(nil)
+		return
+	}
+	c.menu.SetOnSelect(func(m *Menu, o *MenuOption) { f(c, o) })
 }
 
-// SetTabSkip sets the tabskip flag for this control
-func (c *DropMenu) SetTabSkip(b bool) {
-	c.tabSkip = b
+// GetKeymap returns the keymap the menu uses
+func (c *DropMenu) GetKeymap() *Keymap { return c.menu.GetKeymap() }
+
+// SetKeymap sets the keymap the menu uses, nil uses the DefaultKeymap
+func (c *DropMenu) SetKeymap(k *Keymap) {
+	c.menu.SetKeymap(k)
 }
 
 // ShowMenu tells the menu to draw the options
 func (c *DropMenu) ShowMenu() {
 	c.showMenu = true
 	c.menuSelected = true
+	c.dirty = true
 }
 
 // HideMenu tells the menu to hide the options
 func (c *DropMenu) HideMenu() {
 	c.showMenu = false
 	c.menuSelected = false
+	c.dirty = true
+}
+
+// ApplyTheme sets the colors of the control from the theme t
+func (c *DropMenu) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
+	c.borderStyle = t.BorderStyle
+	c.cursorFg, c.cursorBg = t.Focused.Fg, t.Focused.Bg
+	c.menu.ApplyTheme(t)
 }
 
 // HandleEvent handles the termbox event and returns whether it was consumed
 func (c *DropMenu) HandleEvent(event termbox.Event) bool {
-	moveUp := (event.Key == termbox.KeyArrowUp || (c.menu.vimMode && event.Ch == 'k'))
-	moveDown := (event.Key == termbox.KeyArrowDown || (c.menu.vimMode && event.Ch == 'j'))
+	if event.Key == termbox.KeyTab || event.Key == KeyBackTab { // Tabbing is left to the frame
+		return false
+	}
+	move := c.menu.keymap.Match(event, ActionSelectPrev, ActionSelectNext)
+	moveUp, moveDown := move == ActionSelectPrev, move == ActionSelectNext
 	if c.menuSelected {
 		selIdx := c.menu.GetSelectedIndex()
 		if (moveUp && selIdx == 0) || (moveDown && selIdx == (len(c.menu.options)-1)) {
@@ -166,6 +139,43 @@ func (c *DropMenu) HandleEvent(event termbox.Event) bool {
 	return false
 }
 
+// HandleMouse opens and closes the menu when the title is clicked
+// and passes anything over the open menu on to it
+func (c *DropMenu) HandleMouse(ev MouseEvent) bool {
+	if c.showMenu {
+		menuEv := ev.Offset(c.menu.GetX()-c.x, c.menu.GetY()-c.y)
+		if controlContains(c.menu, ev.X+c.x, ev.Y+c.y) || ev.Action == MouseDrag || ev.Action == MouseRelease {
+			if c.menu.HandleMouse(menuEv) {
+				c.menuSelected = true
+				if c.menu.IsDone() {
+					c.HideMenu()
+				}
+				return true
+			}
+			return false
+		}
+	}
+	if ev.Y == 0 && (ev.Action == MouseClick || ev.Action == MouseDoubleClick) {
+		if c.showMenu {
+			c.HideMenu()
+		} else {
+			c.ShowMenu()
+		}
+		return true
+	}
+	return false
+}
+
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *DropMenu) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *DropMenu) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
 // Draw draws the menu
 func (c *DropMenu) Draw() {
 	// The title
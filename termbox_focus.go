@@ -0,0 +1,222 @@
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+// containerControl is a control that holds other controls
+type containerControl interface {
+	Control
+	GetControls() []Control
+}
+
+// focusContainer is a container that keeps track of which
+// of its controls is active
+type focusContainer interface {
+	containerControl
+	GetActiveControl() Control
+	SetActiveControl(Control) bool
+}
+
+// tabOrdered is a container that tabs through its controls in
+// an order other than the order they were added
+type tabOrdered interface {
+	tabOrder() []int
+}
+
+// focusListener is a control that wants to know when it gains or loses focus
+type focusListener interface {
+	OnFocus()
+	OnBlur()
+}
+
+// FocusManager moves focus around a whole tree of controls. There is always
+// at most one focused control, a leaf, and the containers on the way down
+// to it from the root are what make it focused: each one's active control
+// is the next step down the path. Tab and Shift-Tab move into and out of
+// nested containers, and controls with OnFocus and OnBlur methods are told
+// when they gain and lose focus.
+type FocusManager struct {
+	root Control
+}
+
+// CreateFocusManager creates a focus manager for the tree under root
+func CreateFocusManager(root Control) *FocusManager {
+	return &FocusManager{root: root}
+}
+
+// GetRoot returns the top of the tree of controls
+func (m *FocusManager) GetRoot() Control { return m.root }
+
+// GetFocused returns the control that has focus, or nil
+// if there isn't anything in the tree that can have it
+func (m *FocusManager) GetFocused() Control {
+	t := m.root
+	for t != nil {
+		v, ok := t.(focusContainer)
+		if !ok {
+			break
+		}
+		t = v.GetActiveControl()
+	}
+	if t == nil || t.IsTabSkipped() {
+		return nil
+	}
+	return t
+}
+
+// GetFocusableControls returns every control in the tree
+// that can have focus, in the order they're tabbed through
+func (m *FocusManager) GetFocusableControls() []Control {
+	var ret []Control
+	var walk func(Control)
+	walk = func(t Control) {
+		if t != m.root && t.IsTabSkipped() {
+			return
+		}
+		v, ok := t.(containerControl)
+		if !ok {
+			ret = append(ret, t)
+			return
+		}
+		for _, c := range orderedControls(v) {
+			walk(c)
+		}
+	}
+	if m.root != nil {
+		walk(m.root)
+	}
+	return ret
+}
+
+// orderedControls returns the controls in v in tab order
+func orderedControls(v containerControl) []Control {
+	ctls := v.GetControls()
+	o, ok := v.(tabOrdered)
+	if !ok {
+		return ctls
+	}
+	var ret []Control
+	for _, idx := range o.tabOrder() {
+		ret = append(ret, ctls[idx])
+	}
+	return ret
+}
+
+// findPath returns the controls from root down to t, or nil if t isn't in the tree
+func findPath(root, t Control) []Control {
+	if root == t {
+		return []Control{root}
+	}
+	if v, ok := root.(containerControl); ok {
+		for _, c := range v.GetControls() {
+			if p := findPath(c, t); p != nil {
+				return append([]Control{root}, p...)
+			}
+		}
+	}
+	return nil
+}
+
+// Focus gives focus to the control t. It returns false if t isn't in the tree.
+func (m *FocusManager) Focus(t Control) bool {
+	path := findPath(m.root, t)
+	if path == nil {
+		return false
+	}
+	old := m.GetFocused()
+	for k := 0; k < len(path)-1; k++ {
+		if v, ok := path[k].(focusContainer); ok {
+			v.SetActiveControl(path[k+1])
+		}
+	}
+	m.changed(old)
+	return true
+}
+
+// FocusNext moves focus to the next control in the tree, wrapping
+// around at the end. It returns whether the focus moved.
+func (m *FocusManager) FocusNext() bool { return m.focusStep(1) }
+
+// FocusPrev moves focus to the previous control in the tree, wrapping
+// around at the start. It returns whether the focus moved.
+func (m *FocusManager) FocusPrev() bool { return m.focusStep(-1) }
+
+// focusStep moves focus dir places through the focusable controls
+func (m *FocusManager) focusStep(dir int) bool {
+	ctls := m.GetFocusableControls()
+	if len(ctls) == 0 {
+		return false
+	}
+	curr := m.GetFocused()
+	pos := -1
+	for k, v := range ctls {
+		if v == curr {
+			pos = k
+			break
+		}
+	}
+	if pos == -1 && dir < 0 {
+		pos = 0
+	}
+	next := ctls[(pos+dir+len(ctls))%len(ctls)]
+	if next == curr {
+		return false
+	}
+	return m.Focus(next)
+}
+
+// Refresh makes the root and the controls on the way down to the focused
+// control active, and every other control inactive
+func (m *FocusManager) Refresh() {
+	if m.root != nil {
+		m.root.SetActive(true)
+	}
+}
+
+// changed refreshes the active controls and, if the focus has moved
+// away from old, lets the controls know
+func (m *FocusManager) changed(old Control) {
+	m.Refresh()
+	curr := m.GetFocused()
+	if curr == old {
+		return
+	}
+	if v, ok := old.(focusListener); ok {
+		v.OnBlur()
+	}
+	if v, ok := curr.(focusListener); ok {
+		v.OnFocus()
+	}
+}
+
+// HandleEvent sends the event to the focused control. If it isn't consumed
+// Tab and Shift-Tab move the focus. Mouse events go to the root, which
+// moves the focus to whatever was clicked.
+func (m *FocusManager) HandleEvent(event termbox.Event) bool {
+	if m.root == nil {
+		return false
+	}
+	if event.Type == termbox.EventMouse {
+		old := m.GetFocused()
+		var ret bool
+		if _, ok := m.root.(mouseControl); ok {
+			ret = SendMouseEvent(m.root, ToMouseEvent(event).Offset(m.root.GetX(), m.root.GetY()))
+		} else if ret = m.root.HandleEvent(event); ret {
+			markLeafDirty(m.root)
+		}
+		m.changed(old)
+		return ret
+	}
+	if curr := m.GetFocused(); curr != nil && curr.HandleEvent(event) {
+		markLeafDirty(curr)
+		return true
+	}
+	if event.Type == termbox.EventKey {
+		switch event.Key {
+		case termbox.KeyTab:
+			return m.FocusNext()
+		case KeyBackTab:
+			return m.FocusPrev()
+		}
+	}
+	return false
+}
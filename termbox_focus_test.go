@@ -0,0 +1,113 @@
+package termboxUtil_test
+
+import (
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func TestTabAndBackTabMoveFocus(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	frm := termboxUtil.CreateFrame(0, 0, 20, 6, fg, bg)
+	a := termboxUtil.CreateInputField(0, 0, 10, 1, fg, bg)
+	b := termboxUtil.CreateInputField(0, 1, 10, 1, fg, bg)
+	drop := termboxUtil.CreateDropMenu("Pick", []string{"x", "y"}, 0, 2, 10, 3, fg, bg, bg, fg)
+	frm.AddControl(a)
+	frm.AddControl(b)
+	frm.AddControl(drop)
+	m := termboxUtil.CreateFocusManager(frm)
+	tab, backTab := screentest.Key(termbox.KeyTab), screentest.Key(termboxUtil.KeyBackTab)
+	for idx, step := range []struct {
+		ev   termbox.Event
+		want termboxUtil.Control
+		name string
+	}{
+		{tab, b, "b"}, {backTab, a, "a"}, {tab, b, "b"}, {tab, drop, "the drop menu"}, {backTab, b, "b"},
+	} {
+		m.HandleEvent(step.ev)
+		if m.GetFocused() != step.want {
+			t.Fatalf("step %d: expected focus to be on %s", idx, step.name)
+		}
+	}
+}
+
+// focusProbe is a control that counts how often it gains and loses focus
+type focusProbe struct {
+	termboxUtil.BaseControl
+	focused, blurred int
+}
+
+func (c *focusProbe) HandleEvent(termbox.Event) bool { return false }
+func (c *focusProbe) Draw()                          {}
+func (c *focusProbe) OnFocus()                       { c.focused++ }
+func (c *focusProbe) OnBlur()                        { c.blurred++ }
+
+func TestFocusAcrossNestedFrames(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	outer := termboxUtil.CreateFrame(0, 0, 30, 10, fg, bg)
+	inner := termboxUtil.CreateFrame(0, 1, 20, 4, fg, bg)
+	a := termboxUtil.CreateInputField(0, 0, 10, 1, fg, bg)
+	b := termboxUtil.CreateInputField(0, 0, 10, 1, fg, bg)
+	c := &focusProbe{BaseControl: termboxUtil.CreateBaseControl(0, 1, 10, 1, fg, bg)}
+	d := termboxUtil.CreateInputField(0, 6, 10, 1, fg, bg)
+	outer.AddControl(a)
+	inner.AddControl(b)
+	inner.AddControl(c)
+	outer.AddControl(inner)
+	outer.AddControl(d)
+	m := termboxUtil.CreateFocusManager(outer)
+	m.Refresh()
+	leaves := []termboxUtil.Control{a, b, c, d}
+	names := []string{"a", "b", "c", "d"}
+	check := func(step string, want int) {
+		t.Helper()
+		if m.GetFocused() != leaves[want] {
+			t.Fatalf("%s: expected focus on %s", step, names[want])
+		}
+		for idx, l := range leaves {
+			if l.IsActive() != (idx == want) {
+				t.Fatalf("%s: expected only %s to be active, %s is %v", step, names[want], names[idx], l.IsActive())
+			}
+		}
+	}
+	check("start", 0)
+	tab, backTab := screentest.Key(termbox.KeyTab), screentest.Key(termboxUtil.KeyBackTab)
+	for idx, want := range []int{1, 2, 3, 0} {
+		m.HandleEvent(tab)
+		check("tab "+names[idx], want)
+	}
+	m.HandleEvent(backTab)
+	check("backtab", 3)
+	m.HandleEvent(backTab)
+	check("backtab into the inner frame", 2)
+	if c.focused != 2 || c.blurred != 1 {
+		t.Errorf("expected c to be focused twice and blurred once, got %d and %d", c.focused, c.blurred)
+	}
+
+	// Drawing doesn't move focus around
+	screentest.CreateScreen(31, 11).Draw(outer)
+	check("draw", 2)
+	m.HandleEvent(screentest.Rune('x'))
+	if a.GetValue() != "" || d.GetValue() != "" {
+		t.Error("expected typing not to go to a field that isn't focused")
+	}
+}
+
+func TestFocusSkipsLeadingLabel(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	frm := termboxUtil.CreateFrame(0, 0, 20, 4, fg, bg)
+	frm.AddControl(termboxUtil.CreateLabel("Name", 1, 1, 4, 1, fg, bg))
+	fld := termboxUtil.CreateInputField(6, 1, 10, 1, fg, bg)
+	frm.AddControl(fld)
+	m := termboxUtil.CreateFocusManager(frm)
+	m.Refresh()
+	if m.GetFocused() != fld || !fld.IsActive() {
+		t.Fatalf("expected the field after the label to be focused and active, it's active: %v", fld.IsActive())
+	}
+	screentest.CreateScreen(21, 5).Send(frm, screentest.Runes("bob")...)
+	if fld.GetValue() != "bob" {
+		t.Errorf("expected typing to go to the field, got %q", fld.GetValue())
+	}
+}
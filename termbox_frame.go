@@ -2,6 +2,7 @@ package termboxUtil
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/nsf/termbox-go"
 )
@@ -9,32 +10,28 @@ import (
 // Frame is a frame for holding other elements
 // It manages it's own x/y, tab index
 type Frame struct {
-	id                  string
-	x, y, width, height int
-	tabIdx              int
-	fg, bg              termbox.Attribute
-	activeFg, activeBg  termbox.Attribute
-	bordered            bool
-	controls            []termboxControl
-	tabSkip             bool
-	active              bool
-	title               string
-	status              string
-	rightStatus         string
+	BaseControl
+	tabIdx      int
+	theme       *Theme
+	controls    []Control
+	tabOrders   map[Control]int
+	placements  map[Control]Placement
+	title       string
+	status      string
+	rightStatus string
+	mouse       mouseRouter
 }
 
 // CreateFrame creates a Frame at x, y that is w by h
 func CreateFrame(x, y, w, h int, fg, bg termbox.Attribute) *Frame {
-	c := Frame{x: x, y: y, width: w, height: h,
-		fg: fg, bg: bg, activeFg: fg, activeBg: bg,
-		bordered: true,
-	}
+	c := Frame{BaseControl: CreateBaseControl(x, y, w, h, fg, bg)}
+	c.bordered = true
 	return &c
 }
 
-func (c *Frame) SetTitle(title string) { c.title = title }
+func (c *Frame) SetTitle(title string) { c.title, c.dirty = title, true }
 
-func (c *Frame) SetStatus(status string) { c.status = status }
+func (c *Frame) SetStatus(status string) { c.status, c.dirty = status, true }
 
 // Setting color attributes on a frame trickles down to its controls
 func (c *Frame) SetActiveFgColor(fg termbox.Attribute) {
@@ -42,15 +39,17 @@ func (c *Frame) SetActiveFgColor(fg termbox.Attribute) {
 	for _, v := range c.controls {
 		v.SetActiveFgColor(fg)
 	}
+	c.dirty = true
 }
 func (c *Frame) SetActiveBgColor(bg termbox.Attribute) {
 	c.activeBg = bg
 	for _, v := range c.controls {
 		v.SetActiveBgColor(bg)
 	}
+	c.dirty = true
 }
 func (c *Frame) SetActive(a bool) {
-	c.active = a
+	c.BaseControl.SetActive(a)
 	for idx := range c.controls {
 		if idx == c.tabIdx && a {
 			c.controls[idx].SetActive(true)
@@ -59,120 +58,253 @@ func (c *Frame) SetActive(a bool) {
 		}
 	}
 }
-func (c *Frame) IsActive() bool { return c.active }
-
-// GetID returns this control's ID
-func (c *Frame) GetID() string { return c.id }
-
-// SetID sets this control's ID
-func (c *Frame) SetID(newID string) { c.id = newID }
-
-// GetX returns the x position of the frame
-func (c *Frame) GetX() int { return c.x }
-
-// SetX sets the x position of the frame
-func (c *Frame) SetX(x int) { c.x = x }
-
-// GetY returns the y position of the frame
-func (c *Frame) GetY() int { return c.y }
 
-// SetY sets the y position of the frame
-func (c *Frame) SetY(y int) { c.y = y }
-
-// GetWidth returns the current width of the frame
-func (c *Frame) GetWidth() int { return c.width }
-
-// SetWidth sets the current width of the frame
-func (c *Frame) SetWidth(w int) { c.width = w }
+// AddControl adds a control to the frame, applying the frame's theme to it
+func (c *Frame) AddControl(t Control) {
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
+	c.controls = append(c.controls, t)
+	c.dirty = true
+}
 
-// GetHeight returns the current height of the frame
-func (c *Frame) GetHeight() int { return c.height }
+// AddPlacedControl adds a control to the frame that is moved and sized
+// by the placement p whenever the frame is drawn
+func (c *Frame) AddPlacedControl(t Control, p Placement) {
+	c.AddControl(t)
+	c.SetPlacement(t, p)
+}
 
-// SetHeight sets the current height of the frame
-func (c *Frame) SetHeight(h int) { c.height = h }
+// SetPlacement sets where the control t goes in the frame
+func (c *Frame) SetPlacement(t Control, p Placement) {
+	if c.placements == nil {
+		c.placements = make(map[Control]Placement)
+	}
+	c.placements[t] = p
+	c.dirty = true
+}
 
-// GetFgColor returns the foreground color
-func (c *Frame) GetFgColor() termbox.Attribute { return c.fg }
+// GetPlacement returns where the control t goes in the frame, and
+// false if it doesn't have a placement
+func (c *Frame) GetPlacement(t Control) (Placement, bool) {
+	p, ok := c.placements[t]
+	return p, ok
+}
 
-// SetFgColor sets the foreground color
-func (c *Frame) SetFgColor(fg termbox.Attribute) { c.fg = fg }
+// ClearPlacement leaves the control t wherever it is now
+func (c *Frame) ClearPlacement(t Control) {
+	delete(c.placements, t)
+	c.dirty = true
+}
 
-// GetBgColor returns the background color
-func (c *Frame) GetBgColor() termbox.Attribute { return c.bg }
+// placeControls moves and sizes the placed controls to fit the frame
+func (c *Frame) placeControls() {
+	if len(c.placements) == 0 {
+		return
+	}
+	x, y, w, h := c.GetClipRect()
+	x, y = x-c.x, y-c.y
+	for _, t := range c.controls {
+		if p, ok := c.placements[t]; ok {
+			p.Apply(t, x, y, w, h)
+		}
+	}
+}
 
-// SetBgColor sets the current background color
-func (c *Frame) SetBgColor(bg termbox.Attribute) { c.bg = bg }
+// SetTheme applies the theme t to the frame and everything in it.
+// Controls added to the frame later get the theme as well.
+func (c *Frame) SetTheme(t *Theme) { ApplyTheme(c, t) }
 
-// IsBordered returns true or false if this frame has a border
-func (c *Frame) IsBordered() bool { return c.bordered }
+// GetTheme returns the theme applied to the frame, or nil
+func (c *Frame) GetTheme() *Theme { return c.theme }
 
-// SetBordered sets whether we render a border around the frame
-func (c *Frame) SetBordered(b bool) { c.bordered = b }
+// ResetTabIndex moves focus to the first control in the tab order
+func (c *Frame) ResetTabIndex() {
+	for _, k := range c.tabOrder() {
+		if !c.controls[k].IsTabSkipped() {
+			c.tabIdx = k
+			break
+		}
+	}
+}
 
-// IsTabSkipped returns whether this modal has it's tabskip flag set
-func (c *Frame) IsTabSkipped() bool { return c.tabSkip }
+// SetTabOrder sets where the control t comes when tabbing through the frame.
+// Controls with an order above 0 come first, lowest first, then the rest
+// in the order they were added (like tabindex in HTML).
+func (c *Frame) SetTabOrder(t Control, order int) {
+	if c.tabOrders == nil {
+		c.tabOrders = make(map[Control]int)
+	}
+	if order <= 0 {
+		delete(c.tabOrders, t)
+		return
+	}
+	c.tabOrders[t] = order
+}
 
-// SetTabSkip sets the tabskip flag for this control
-func (c *Frame) SetTabSkip(b bool) { c.tabSkip = b }
+// GetTabOrder returns the tab order of the control t, 0 if it hasn't been set
+func (c *Frame) GetTabOrder(t Control) int { return c.tabOrders[t] }
 
-// AddControl adds a control to the frame
-func (c *Frame) AddControl(t termboxControl) { c.controls = append(c.controls, t) }
+// tabOrder returns the indexes of the controls in the order they're tabbed through
+func (c *Frame) tabOrder() []int {
+	ret := make([]int, len(c.controls))
+	for k := range ret {
+		ret[k] = k
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		oi, oj := c.tabOrders[c.controls[ret[i]]], c.tabOrders[c.controls[ret[j]]]
+		if oi > 0 && oj > 0 {
+			return oi < oj
+		}
+		return oi > 0 && oj <= 0
+	})
+	return ret
+}
 
-func (c *Frame) ResetTabIndex() {
-	for k, v := range c.controls {
-		if !v.IsTabSkipped() {
-			c.tabIdx = k
-			break
+// tabPosition returns where the active control is in the tab order
+func (c *Frame) tabPosition(order []int) int {
+	for k, v := range order {
+		if v == c.tabIdx {
+			return k
 		}
 	}
+	return 0
 }
 
 // GetActiveControl returns the control at tabIdx
-func (c *Frame) GetActiveControl() termboxControl {
-	if len(c.controls) >= c.tabIdx {
-		if c.controls[c.tabIdx].IsTabSkipped() {
-			c.FindNextTabStop()
+func (c *Frame) GetActiveControl() Control {
+	if c.tabIdx < len(c.controls) {
+		if c.controls[c.tabIdx].IsTabSkipped() && c.FindNextTabStop() {
+			// The control it moved on to is the active one now
+			c.SetActive(c.active)
 		}
 		return c.controls[c.tabIdx]
 	}
 	return nil
 }
 
+// SetActiveControl makes t the active control, returning false if t isn't in the frame
+func (c *Frame) SetActiveControl(t Control) bool {
+	for idx := range c.controls {
+		if c.controls[idx] == t {
+			c.tabIdx = idx
+			c.SetActive(c.active)
+			return true
+		}
+	}
+	return false
+}
+
 // GetControls returns a slice of all controls
-func (c *Frame) GetControls() []termboxControl {
+func (c *Frame) GetControls() []Control {
 	return c.controls
 }
 
 // GetControl returns the control at index i
-func (c *Frame) GetControl(idx int) termboxControl {
-	if len(c.controls) >= idx {
+func (c *Frame) GetControl(idx int) Control {
+	if idx >= 0 && idx < len(c.controls) {
 		return c.controls[idx]
 	}
 	return nil
 }
 
+// IndexOf returns the index of the control t in the frame, or -1
+func (c *Frame) IndexOf(t Control) int { return indexOfControl(c.controls, t) }
+
+// InsertControl inserts a control into the frame at index idx,
+// applying the frame's theme to it
+func (c *Frame) InsertControl(idx int, t Control) {
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
+	c.controls, c.tabIdx = insertControlAt(c.controls, c.tabIdx, idx, t)
+	c.SetActive(c.active)
+	c.dirty = true
+}
+
+// RemoveControl removes the control t from the frame,
+// returning false if it isn't in the frame
+func (c *Frame) RemoveControl(t Control) bool {
+	idx := c.IndexOf(t)
+	if idx == -1 {
+		return false
+	}
+	c.dirty = true
+	c.controls, c.tabIdx = removeControlAt(c.controls, c.tabIdx, idx)
+	delete(c.tabOrders, t)
+	delete(c.placements, t)
+	if c.mouse.capture == t {
+		c.mouse.capture = nil
+	}
+	t.SetActive(false)
+	c.SetActive(c.active)
+	return true
+}
+
+// ReplaceControl puts the control t in the frame in place of old, with the
+// same tab order and placement. It returns false if old isn't in the frame.
+func (c *Frame) ReplaceControl(old, t Control) bool {
+	idx := c.IndexOf(old)
+	if idx == -1 {
+		return false
+	}
+	c.dirty = true
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
+	c.controls[idx] = t
+	if o, ok := c.tabOrders[old]; ok {
+		delete(c.tabOrders, old)
+		c.tabOrders[t] = o
+	}
+	if p, ok := c.placements[old]; ok {
+		delete(c.placements, old)
+		c.placements[t] = p
+	}
+	if c.mouse.capture == old {
+		c.mouse.capture = nil
+	}
+	old.SetActive(false)
+	c.SetActive(c.active)
+	return true
+}
+
 // GetControlCount returns the number of controls contained
 func (c *Frame) GetControlCount() int {
 	return len(c.controls)
 }
 
 // GetLastControl returns the last control contained
-func (c *Frame) GetLastControl() termboxControl {
+func (c *Frame) GetLastControl() Control {
 	return c.controls[len(c.controls)-1]
 }
 
 // RemoveAllControls clears the control slice
 func (c *Frame) RemoveAllControls() {
-	c.controls = []termboxControl{}
+	c.controls = []Control{}
+	c.tabOrders = nil
+	c.placements = nil
+	c.tabIdx = 0
+	c.dirty = true
+}
+
+// GetClipRect returns the area that controls in the frame can draw to.
+// This is the inside of the border if the frame is bordered.
+func (c *Frame) GetClipRect() (int, int, int, int) {
+	if c.bordered {
+		return c.x + 1, c.y + 1, c.width - 1, c.height - 1
+	}
+	return c.x, c.y, c.width + 1, c.height + 1
 }
 
 // DrawControl figures out the relative position of the control,
-// sets it, draws it, then resets it.
-func (c *Frame) DrawControl(t termboxControl) {
+// sets it, draws it clipped to the frame, then resets it.
+func (c *Frame) DrawControl(t Control) {
 	ctlX, ctlY := t.GetX(), t.GetY()
 	t.SetX((c.GetX() + ctlX))
 	t.SetY((c.GetY() + ctlY))
-	t.Draw()
+	clipX, clipY, clipW, clipH := c.GetClipRect()
+	DrawClipped(t, clipX, clipY, clipW, clipH)
 	t.SetX(ctlX)
 	t.SetY(ctlY)
 }
@@ -188,49 +320,117 @@ func (c *Frame) GetBottomY() int {
 	return ret
 }
 
+// ApplyTheme sets the colors of the frame and everything in it from the theme t
+func (c *Frame) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
+	c.borderStyle = t.BorderStyle
+	c.theme = t
+	for _, v := range c.controls {
+		ApplyTheme(v, t)
+	}
+}
+
 // HandleEvent accepts the termbox event and returns whether it was consumed
 func (c *Frame) HandleEvent(event termbox.Event) bool {
-	// If the currently active control consumes the event, we don't need to handle it
-	if c.controls[c.tabIdx].HandleEvent(event) {
-		c.rightStatus = fmt.Sprintf("C (%d/%d)", c.tabIdx, len(c.controls))
+	// Focus is handled for the frame and everything in it as a whole
+	if CreateFocusManager(c).HandleEvent(event) {
+		c.setRightStatus(fmt.Sprintf("C (%d/%d)", c.tabIdx, len(c.controls)))
 		return true
 	}
-	// All that a frame cares about is tabbing around
-	if event.Key == termbox.KeyTab {
-		ret := !c.IsOnLastControl()
-		c.FindNextTabStop()
-		c.rightStatus = fmt.Sprintf("N (%d/%d)", c.tabIdx, len(c.controls))
-		return ret
-	}
-	c.rightStatus = fmt.Sprintf("B (%d/%d)", c.tabIdx, len(c.controls))
+	c.setRightStatus(fmt.Sprintf("B (%d/%d)", c.tabIdx, len(c.controls)))
 	return false
 }
 
+// setRightStatus sets the status drawn on the right of the bottom border
+func (c *Frame) setRightStatus(s string) {
+	if c.rightStatus != s {
+		c.rightStatus, c.dirty = s, true
+	}
+}
+
+// HandleMouse sends the mouse event on to the control under it,
+// giving that control focus if it was clicked
+func (c *Frame) HandleMouse(ev MouseEvent) bool {
+	clipX, clipY, clipW, clipH := c.GetClipRect()
+	if ev.Action != MouseDrag && ev.Action != MouseRelease &&
+		!inRect(ev.X+c.x, ev.Y+c.y, clipX, clipY, clipW, clipH) {
+		return false
+	}
+	c.placeControls()
+	idx, ret := c.mouse.route(c.controls, ev, 0, 0)
+	if idx >= 0 && !c.controls[idx].IsTabSkipped() {
+		c.SetActiveControl(c.controls[idx])
+	}
+	return ret
+}
+
 // FindNextTabStop finds the next control that can be tabbed to
 // A return of true means it found a different one than we started on.
 func (c *Frame) FindNextTabStop() bool {
+	return c.findTabStop(1)
+}
+
+// FindPrevTabStop finds the previous control that can be tabbed to
+// A return of true means it found a different one than we started on.
+func (c *Frame) FindPrevTabStop() bool {
+	return c.findTabStop(-1)
+}
+
+// findTabStop moves through the tab order in the direction dir
+// until it finds a control that isn't tab skipped
+func (c *Frame) findTabStop(dir int) bool {
+	if len(c.controls) == 0 {
+		return false
+	}
 	startTab := c.tabIdx
-	c.tabIdx = (c.tabIdx + 1) % len(c.controls)
-	for c.controls[c.tabIdx].IsTabSkipped() {
-		c.tabIdx = (c.tabIdx + 1) % len(c.controls)
-		if c.tabIdx == startTab {
+	order := c.tabOrder()
+	start := c.tabPosition(order)
+	pos := start
+	for {
+		pos = (pos + dir + len(order)) % len(order)
+		if pos == start || !c.controls[order[pos]].IsTabSkipped() {
 			break
 		}
 	}
+	c.tabIdx = order[pos]
 	return c.tabIdx != startTab
 }
 
 // IsOnLastControl returns true if the active control
-// is the last control that isn't tab skippable.
+// is the last control in the tab order that isn't tab skippable.
 func (c *Frame) IsOnLastControl() bool {
-	for _, v := range c.controls[c.tabIdx+1:] {
-		if !v.IsTabSkipped() {
+	order := c.tabOrder()
+	for _, v := range order[c.tabPosition(order)+1:] {
+		if !c.controls[v].IsTabSkipped() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsOnFirstControl returns true if the active control
+// is the first control in the tab order that isn't tab skippable.
+func (c *Frame) IsOnFirstControl() bool {
+	order := c.tabOrder()
+	for _, v := range order[:c.tabPosition(order)] {
+		if !c.controls[v].IsTabSkipped() {
 			return false
 		}
 	}
 	return true
 }
 
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *Frame) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *Frame) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
 // Draw outputs the Scoll Frame on the screen
 func (c *Frame) Draw() {
 	maxWidth := c.width
@@ -239,6 +439,9 @@ func (c *Frame) Draw() {
 	startX := c.x
 	startY := c.y
 	borderFg, borderBg := c.fg, c.bg
+	if c.theme != nil {
+		borderFg, borderBg = c.theme.Border.Fg, c.theme.Border.Bg
+	}
 	if c.active {
 		borderFg, borderBg = c.activeFg, c.activeBg
 	}
@@ -246,9 +449,9 @@ func (c *Frame) Draw() {
 		// Clear the framed area
 		FillWithChar(' ', c.x, c.y, c.x+c.width, c.y+c.height, borderFg, borderBg)
 		if c.title == "" {
-			DrawBorder(c.x, c.y, c.x+c.width, c.y+c.height, borderFg, borderBg)
+			DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", borderFg, borderBg)
 		} else {
-			DrawBorderWithTitle(c.x, c.y, c.x+c.width, c.y+c.height, " "+c.title+" ", borderFg, borderBg)
+			DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, " "+c.title+" ", borderFg, borderBg)
 		}
 		maxWidth--
 		maxHeight--
@@ -257,18 +460,14 @@ func (c *Frame) Draw() {
 		startX++
 		startY++
 	}
+	c.placeControls()
 	for idx := range c.controls {
-		if idx == c.tabIdx {
-			c.controls[idx].SetActive(true)
-		} else {
-			c.controls[idx].SetActive(false)
-		}
 		c.DrawControl(c.controls[idx])
 	}
 	if c.status != "" {
 		DrawStringAtPoint(" "+c.status+" ", c.x+1, c.y+c.height, borderFg, borderBg)
 	}
 	if c.rightStatus != "" {
-		DrawStringAtPoint(" "+c.rightStatus+" ", (c.x+c.width)-len(c.rightStatus)-2, c.y+c.height, borderFg, borderBg)
+		DrawStringAtPoint(" "+c.rightStatus+" ", (c.x+c.width)-TextWidth(c.rightStatus)-2, c.y+c.height, borderFg, borderBg)
 	}
 }
@@ -2,52 +2,45 @@ package termboxUtil
 
 import (
 	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"github.com/nsf/termbox-go"
 )
 
 // InputField is a field for inputting text
 type InputField struct {
-	id                  string
-	title               string
-	value               string
-	x, y, width, height int
-	cursor              int
-	fg, bg              termbox.Attribute
-	activeFg, activeBg  termbox.Attribute
-	cursorFg, cursorBg  termbox.Attribute
-	bordered            bool
-	wrap                bool
-	multiline           bool
-	tabSkip             bool
-	active              bool
-	justified           bool
-
-	filter func(*InputField, string, string) string
+	BaseControl
+	title              string
+	value              string
+	cursor             int
+	cursorFg, cursorBg termbox.Attribute
+	errorFg, errorBg   termbox.Attribute
+	keymap             *Keymap
+	wrap               bool
+	multiline          bool
+	justified          bool
+
+	blinkRate    time.Duration
+	blinkStart   time.Time
+	cursorHidden bool
+
+	filter    func(*InputField, string, string) string
+	validator func(string) error
+	onChange  func(*InputField, string, string)
 }
 
 // CreateInputField creates an input field at x, y that is w by h
 func CreateInputField(x, y, w, h int, fg, bg termbox.Attribute) *InputField {
-	c := InputField{x: x, y: y, width: w, height: h,
-		fg: fg, bg: bg, cursorFg: bg, cursorBg: fg, activeFg: fg, activeBg: bg,
+	c := InputField{BaseControl: CreateBaseControl(x, y, w, h, fg, bg),
+		cursorFg: bg, cursorBg: fg,
+		errorFg: termbox.ColorRed | termbox.AttrBold, errorBg: bg,
 	}
 	c.filter = func(fld *InputField, o, n string) string { return n }
 	return &c
 }
 
-func (c *InputField) SetTitle(title string)                 { c.title = title }
-func (c *InputField) SetActiveFgColor(fg termbox.Attribute) { c.activeFg = fg }
-func (c *InputField) SetActiveBgColor(bg termbox.Attribute) { c.activeBg = bg }
-func (c *InputField) SetActive(a bool)                      { c.active = a }
-func (c *InputField) IsActive() bool                        { return c.active }
-
-// GetID returns this control's ID
-func (c *InputField) GetID() string { return c.id }
-
-// SetID sets this control's ID
-func (c *InputField) SetID(newID string) {
-	c.id = newID
-}
+func (c *InputField) SetTitle(title string) { c.title, c.dirty = title, true }
 
 // GetValue gets the current text that is in the InputField
 func (c *InputField) GetValue() string { return c.value }
@@ -55,68 +48,89 @@ func (c *InputField) GetValue() string { return c.value }
 // SetValue sets the current text in the InputField to s
 func (c *InputField) SetValue(s string) {
 	c.value = s
+	c.dirty = true
 }
 
-// GetX returns the x position of the input field
-func (c *InputField) GetX() int { return c.x }
-
-// SetX sets the x position of the input field
-func (c *InputField) SetX(x int) { c.x = x }
-
-// GetY returns the y position of the input field
-func (c *InputField) GetY() int { return c.y }
-
-// SetY sets the y position of the input field
-func (c *InputField) SetY(y int) { c.y = y }
-
-// GetWidth returns the current width of the input field
-func (c *InputField) GetWidth() int { return c.width }
+func (c *InputField) SetCursorFg(fg termbox.Attribute) { c.cursorFg, c.dirty = fg, true }
 
-// SetWidth sets the current width of the input field
-func (c *InputField) SetWidth(w int) { c.width = w }
-
-// GetHeight returns the current height of the input field
-func (c *InputField) GetHeight() int { return c.height }
+func (c *InputField) GetCursorFg() termbox.Attribute { return c.cursorFg }
 
-// SetHeight sets the current height of the input field
-func (c *InputField) SetHeight(h int) { c.height = h }
+func (c *InputField) SetCursorBg(bg termbox.Attribute) { c.cursorBg, c.dirty = bg, true }
 
-// GetFgColor returns the foreground color
-func (c *InputField) GetFgColor() termbox.Attribute { return c.fg }
+func (c *InputField) GetCursorBg() termbox.Attribute { return c.cursorBg }
 
-// SetFgColor sets the foreground color
-func (c *InputField) SetFgColor(fg termbox.Attribute) { c.fg = fg }
+// SetCursorBlink sets how long the cursor is shown and then hidden for
+// while the field is active, 0 stops it blinking. It's animated by an
+// App's ticks (or TickControls).
+func (c *InputField) SetCursorBlink(rate time.Duration) {
+	c.blinkRate = rate
+	c.blinkStart, c.cursorHidden = time.Time{}, false
+	c.dirty = true
+}
 
-// GetBgColor returns the background color
-func (c *InputField) GetBgColor() termbox.Attribute { return c.bg }
+// GetCursorBlink returns how long the cursor is shown and then hidden
+// for, 0 if it doesn't blink
+func (c *InputField) GetCursorBlink() time.Duration { return c.blinkRate }
 
-// SetBgColor sets the current background color
-func (c *InputField) SetBgColor(bg termbox.Attribute) { c.bg = bg }
+// IsAnimating returns whether the cursor is blinking
+func (c *InputField) IsAnimating() bool {
+	return c.blinkRate > 0 && (c.active || c.cursorHidden)
+}
 
-func (c *InputField) SetCursorFg(fg termbox.Attribute) { c.cursorFg = fg }
+// Tick shows or hides the cursor when it's blinking
+func (c *InputField) Tick(now time.Time) bool {
+	hidden := false
+	if c.blinkRate > 0 && c.active {
+		if c.blinkStart.IsZero() {
+			c.blinkStart = now
+		}
+		hidden = (now.Sub(c.blinkStart)/c.blinkRate)%2 == 1
+	} else {
+		c.blinkStart = time.Time{}
+	}
+	if hidden == c.cursorHidden {
+		return false
+	}
+	c.cursorHidden = hidden
+	return true
+}
 
-func (c *InputField) GetCursorFg() termbox.Attribute { return c.cursorFg }
+// SetErrorFg sets the color the text is drawn in when it isn't valid
+func (c *InputField) SetErrorFg(fg termbox.Attribute) { c.errorFg, c.dirty = fg, true }
 
-func (c *InputField) SetCursorBg(bg termbox.Attribute) { c.cursorBg = bg }
+// GetErrorFg returns the color the text is drawn in when it isn't valid
+func (c *InputField) GetErrorFg() termbox.Attribute { return c.errorFg }
 
-func (c *InputField) GetCursorBg() termbox.Attribute { return c.cursorBg }
+// SetErrorBg sets the background of the text when it isn't valid
+func (c *InputField) SetErrorBg(bg termbox.Attribute) { c.errorBg, c.dirty = bg, true }
 
-// IsBordered returns true or false if this input field has a border
-func (c *InputField) IsBordered() bool { return c.bordered }
+// GetErrorBg returns the background of the text when it isn't valid
+func (c *InputField) GetErrorBg() termbox.Attribute { return c.errorBg }
 
-// SetBordered sets whether we render a border around the input field
-func (c *InputField) SetBordered(b bool) {
-	c.bordered = b
+// SetValidator sets a function that checks the value, while it returns
+// an error the text is drawn in the error colors. nil turns checking off.
+func (c *InputField) SetValidator(f func(string) error) {
+	c.validator = f
+	c.dirty = true
 }
 
-// IsTabSkipped returns whether this modal has it's tabskip flag set
-func (c *InputField) IsTabSkipped() bool {
-	return c.tabSkip
+// GetError returns what's wrong with the value, or nil if it's valid
+func (c *InputField) GetError() error {
+	if c.validator == nil {
+		return nil
+	}
+	return c.validator(c.value)
 }
 
-// SetTabSkip sets the tabskip flag for this control
-func (c *InputField) SetTabSkip(b bool) {
-	c.tabSkip = b
+// IsValid returns whether the value passes the validator
+func (c *InputField) IsValid() bool { return c.GetError() == nil }
+
+// GetKeymap returns the keymap the input field uses
+func (c *InputField) GetKeymap() *Keymap { return ownKeymap(&c.keymap) }
+
+// SetKeymap sets the keymap the input field uses, nil uses the DefaultKeymap
+func (c *InputField) SetKeymap(k *Keymap) {
+	c.keymap = k
 }
 
 // DoesWrap returns true or false if this input field wraps text
@@ -125,6 +139,7 @@ func (c *InputField) DoesWrap() bool { return c.wrap }
 // SetWrap sets whether we wrap the text at width.
 func (c *InputField) SetWrap(b bool) {
 	c.wrap = b
+	c.dirty = true
 }
 
 // IsMultiline returns true or false if this field can have multiple lines
@@ -133,72 +148,106 @@ func (c *InputField) IsMultiline() bool { return c.multiline }
 // SetMultiline sets whether the field can have multiple lines
 func (c *InputField) SetMultiline(b bool) {
 	c.multiline = b
+	c.dirty = true
 }
 
 func (c *InputField) SetJustified(b bool) {
 	c.justified = b
+	c.dirty = true
+}
+
+// ApplyTheme sets the colors of the control from the theme t
+func (c *InputField) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
+	c.borderStyle = t.BorderStyle
+	c.cursorFg, c.cursorBg = t.Focused.Fg, t.Focused.Bg
+	c.errorFg, c.errorBg = t.Error.Fg, t.Error.Bg
 }
 
 // HandleEvent accepts the termbox event and returns whether it was consumed
 func (c *InputField) HandleEvent(event termbox.Event) bool {
 	prev := c.value
-	if event.Key == termbox.KeyTab { // There is no tabbing in here
+	// The cursor is shown straight away on input, even when it's blinking
+	c.blinkStart, c.cursorHidden = time.Time{}, false
+	if event.Key == termbox.KeyTab || event.Key == KeyBackTab { // There is no tabbing in here
 		return false
 	}
-	if event.Key == termbox.KeyBackspace || event.Key == termbox.KeyBackspace2 {
-		if c.cursor+len(c.value) > 0 {
-			crs := len(c.value)
-			if c.cursor < 0 {
-				crs = c.cursor + len(c.value)
-			}
-			c.value = c.value[:crs-1] + c.value[crs:]
-			//c.value = c.value[:len(c.value)-1]
+	// The cursor is a rune offset from the end of the value, so work on runes
+	// rather than bytes to keep from splitting multi-byte characters
+	val := []rune(c.value)
+	crs := len(val) + c.cursor
+	switch c.keymap.Match(event, ActionDeleteBack, ActionDeleteForward, ActionCursorLeft, ActionCursorRight,
+		ActionCursorStart, ActionCursorEnd, ActionClearToStart, ActionClearToEnd) {
+	case ActionDeleteBack:
+		if crs > 0 {
+			c.value = string(val[:crs-1]) + string(val[crs:])
+		}
+	case ActionDeleteForward:
+		if crs < len(val) {
+			c.value = string(val[:crs]) + string(val[crs+1:])
+			c.cursor++
 		}
-	} else if event.Key == termbox.KeyArrowLeft {
-		if c.cursor+len(c.value) > 0 {
+	case ActionCursorLeft:
+		if crs > 0 {
 			c.cursor--
 		}
-	} else if event.Key == termbox.KeyArrowRight {
+	case ActionCursorRight:
 		if c.cursor < 0 {
 			c.cursor++
 		}
-	} else if event.Key == termbox.KeyCtrlU {
-		// Ctrl+U Clears the Input (before the cursor)
-		c.value = c.value[c.cursor+len(c.value):]
-	} else {
+	case ActionCursorStart:
+		c.cursor = -len(val)
+	case ActionCursorEnd:
+		c.cursor = 0
+	case ActionClearToStart:
+		// Clears the Input (before the cursor)
+		c.value = string(val[crs:])
+	case ActionClearToEnd:
+		// Clears the Input (after the cursor)
+		c.value = string(val[:crs])
+		c.cursor = 0
+	default:
 		// Get the rune to add to our value. Space and Tab are special cases where
 		// we can't use the event's rune directly
 		var ch string
-		switch event.Key {
-		case termbox.KeySpace:
-			ch = " "
-		case termbox.KeyTab:
-			ch = "\t"
-		case termbox.KeyEnter:
+		if c.keymap.Match(event, ActionNewline) == ActionNewline {
 			if c.multiline {
 				ch = "\n"
 			}
-		default:
-			if KeyIsAlphaNumeric(event) || KeyIsSymbol(event) {
-				ch = string(event.Ch)
+		} else {
+			switch event.Key {
+			case termbox.KeySpace:
+				ch = " "
+			case termbox.KeyTab:
+				ch = "\t"
+			default:
+				if KeyIsAlphaNumeric(event) || KeyIsSymbol(event) || KeyIsUnicodeText(event) {
+					ch = string(event.Ch)
+				}
 			}
 		}
 
 		// TODO: Handle newlines
-		if c.cursor+len(c.value) == 0 {
-			c.value = string(ch) + c.value
-		} else if c.cursor == 0 {
-			c.value = c.value + string(ch)
-		} else {
-			strPt1 := c.value[:(len(c.value) + c.cursor)]
-			strPt2 := c.value[(len(c.value) + c.cursor):]
-			c.value = strPt1 + string(ch) + strPt2
-		}
+		c.value = string(val[:crs]) + ch + string(val[crs:])
 	}
 	c.value = c.filter(c, prev, c.value)
+	if c.value != prev && c.onChange != nil {
+		c.onChange(c, prev, c.value)
+	}
 	return true
 }
 
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *InputField) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *InputField) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
 // Draw outputs the input field on the screen
 func (c *InputField) Draw() {
 	maxWidth := c.width
@@ -210,8 +259,15 @@ func (c *InputField) Draw() {
 	if c.active {
 		useFg, useBg = c.activeFg, c.activeBg
 	}
+	if !c.IsValid() {
+		useFg, useBg = c.errorFg, c.errorBg
+	}
+	crsFg, crsBg := c.cursorFg, c.cursorBg
+	if c.cursorHidden {
+		crsFg, crsBg = useFg, useBg
+	}
 	if c.bordered {
-		DrawBorder(c.x, c.y, c.x+c.width, c.y+c.height, useFg, useBg)
+		DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", useFg, useBg)
 		maxWidth--
 		maxHeight--
 		x++
@@ -221,23 +277,14 @@ func (c *InputField) Draw() {
 	}
 
 	var strPt1, strPt2 string
-	var cursorRune rune
-	if len(c.value) > 0 {
-		if c.cursor+len(c.value) == 0 {
-			strPt1 = ""
-			strPt2 = c.value[1:]
-			cursorRune = rune(c.value[0])
-		} else if c.cursor == 0 {
-			strPt1 = c.value
-			strPt2 = ""
-			cursorRune = ' '
-		} else {
-			strPt1 = c.value[:(len(c.value) + c.cursor)]
-			strPt2 = c.value[(len(c.value)+c.cursor)+1:]
-			cursorRune = rune(c.value[len(c.value)+c.cursor])
-		}
+	cursorRune := ' '
+	if val := []rune(c.value); len(val) > 0 && c.cursor < 0 {
+		crs := len(val) + c.cursor
+		strPt1 = string(val[:crs])
+		strPt2 = string(val[crs+1:])
+		cursorRune = val[crs]
 	} else {
-		strPt1, strPt2, cursorRune = "", "", ' '
+		strPt1 = c.value
 	}
 	if c.title != "" {
 		if c.active {
@@ -248,64 +295,84 @@ func (c *InputField) Draw() {
 	}
 	if c.wrap {
 		// Split the text into maxWidth chunks
-		for len(strPt1) > maxWidth {
-			breakAt := maxWidth
-			DrawStringAtPoint(strPt1[:breakAt], x, y, useFg, useBg)
+		for TextWidth(strPt1) > maxWidth {
+			var line string
+			line, strPt1 = splitWrappedLine(strPt1, maxWidth)
+			DrawStringAtPoint(line, x, y, useFg, useBg)
 			x = startX
 			y++
-			strPt1 = strPt1[breakAt:]
 		}
 		x, y = DrawStringAtPoint(strPt1, x, y, useFg, useBg)
 		if x >= maxWidth {
 			y++
 			x = startX
 		}
-		termbox.SetCell(x, y, cursorRune, c.cursorFg, c.cursorBg)
-		x++
+		SetCell(x, y, cursorRune, crsFg, crsBg)
+		x += RuneWidth(cursorRune)
 		if len(strPt2) > 0 {
-			lenLeft := maxWidth - len(strPt1) - 1
-			if lenLeft > 0 && len(strPt2) > lenLeft {
-				DrawStringAtPoint(strPt2[:lenLeft], x+1, y, useFg, useBg)
-				strPt2 = strPt2[lenLeft:]
+			lenLeft := maxWidth - TextWidth(strPt1) - 1
+			if lenLeft > 0 && TextWidth(strPt2) > lenLeft {
+				var line string
+				line, strPt2 = SplitTextAtWidth(strPt2, lenLeft)
+				DrawStringAtPoint(line, x+1, y, useFg, useBg)
 			}
-			for len(strPt2) > maxWidth {
-				breakAt := maxWidth
-				DrawStringAtPoint(strPt2[:breakAt], x, y, useFg, useBg)
+			for TextWidth(strPt2) > maxWidth {
+				var line string
+				line, strPt2 = splitWrappedLine(strPt2, maxWidth)
+				DrawStringAtPoint(line, x, y, useFg, useBg)
 				x = startX
 				y++
-				strPt2 = strPt2[breakAt:]
 			}
 			x, y = DrawStringAtPoint(strPt2, x, y, useFg, useBg)
 		}
 	} else {
-		for len(strPt1)+len(strPt2)+1 > maxWidth {
-			if len(strPt1) >= len(strPt2) {
+		cursorWidth := RuneWidth(cursorRune)
+		for TextWidth(strPt1)+TextWidth(strPt2)+cursorWidth > maxWidth {
+			if TextWidth(strPt1) >= TextWidth(strPt2) {
 				if len(strPt1) == 0 {
 					break
 				}
-				strPt1 = strPt1[1:]
+				strPt1 = string([]rune(strPt1)[1:])
 			} else {
-				strPt2 = strPt2[:len(strPt2)-1]
+				rns := []rune(strPt2)
+				strPt2 = string(rns[:len(rns)-1])
 			}
 		}
-		stX := c.x + len(c.title)
+		stX := c.x + TextWidth(c.title)
 		if c.justified {
-			stX = c.x + c.width - len(strPt1) - len(strPt2) - 1
+			stX = c.x + c.width - TextWidth(strPt1) - TextWidth(strPt2) - cursorWidth
 		}
 		x, y = DrawStringAtPoint(strPt1, stX, c.y, useFg, useBg)
 		if c.active {
-			termbox.SetCell(x, y, cursorRune, c.cursorFg, c.cursorBg)
+			SetCell(x, y, cursorRune, crsFg, crsBg)
 		} else {
-			termbox.SetCell(x, y, cursorRune, useFg, useBg)
+			SetCell(x, y, cursorRune, useFg, useBg)
 		}
-		DrawStringAtPoint(strPt2, x+1, y, useFg, useBg)
+		DrawStringAtPoint(strPt2, x+cursorWidth, y, useFg, useBg)
+	}
+}
+
+// splitWrappedLine splits the next line of wrapped text off str. It always
+// takes at least one rune, even one wider than width, so wrapping moves on.
+func splitWrappedLine(str string, width int) (string, string) {
+	line, rest := SplitTextAtWidth(str, width)
+	if line == "" && str != "" {
+		_, n := utf8.DecodeRuneInString(str)
+		line, rest = str[:n], str[n:]
 	}
+	return line, rest
 }
 
 func (c *InputField) SetTextFilter(filter func(*InputField, string, string) string) {
 	c.filter = filter
 }
 
+// SetOnChange sets a function that is called with the old and new
+// values whenever the user changes the value (after the text filter)
+func (c *InputField) SetOnChange(f func(fld *InputField, o, n string)) {
+	c.onChange = f
+}
+
 // Some handy text filters
 func (c *InputField) InputFieldNumberFilter(fld *InputField, o, n string) string {
 	_, err := strconv.Atoi(n)
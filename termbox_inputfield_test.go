@@ -0,0 +1,33 @@
+package termboxUtil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func TestWrapWideRunesInNarrowField(t *testing.T) {
+	s := screentest.CreateScreen(4, 8)
+	fld := termboxUtil.CreateInputField(0, 0, 2, 7, termbox.ColorWhite, termbox.ColorBlack)
+	fld.SetBordered(true)
+	fld.SetWrap(true)
+	done := make(chan struct{})
+	go func() {
+		s.Send(fld, screentest.Runes("日本語")...)
+		s.Draw(fld)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("drawing wide runes in a field narrower than them never finished")
+	}
+	if fld.GetValue() != "日本語" {
+		t.Errorf("expected the value to be \"日本語\", got %q", fld.GetValue())
+	}
+	s.AssertRune(t, 1, 1, '日')
+	s.AssertRune(t, 1, 2, '本')
+}
@@ -6,27 +6,29 @@ import (
 
 // InputModal A modal for text input
 type InputModal struct {
-	id                  string
-	title               string
-	text                string
-	input               *InputField
-	x, y, width, height int
-	showHelp            bool
-	cursor              int
-	bg, fg              termbox.Attribute
-	activeFg, activeBg  termbox.Attribute
-	isDone              bool
-	isAccepted          bool
-	isVisible           bool
-	bordered            bool
-	tabSkip             bool
-	inputSelected       bool
-	active              bool
-}
-
-// CreateInputModal Create an input modal with the given attributes
+	BaseControl
+	title         string
+	text          string
+	input         *InputField
+	showHelp      bool
+	cursor        int
+	isDone        bool
+	isAccepted    bool
+	isVisible     bool
+	theme         *Theme
+	keymap        *Keymap
+	inputSelected bool
+
+	onSubmit func(*InputModal, string)
+	onCancel func(*InputModal)
+}
+
+// CreateInputModal Create an input modal with the given attributes.
+// x, y, width and height are only where it starts out, push it with
+// ModalStack.PushPlaced to have it placed on the screen as that's resized.
 func CreateInputModal(title string, x, y, width, height int, fg, bg termbox.Attribute) *InputModal {
-	c := InputModal{title: title, x: x, y: y, width: width, height: height, fg: fg, bg: bg, bordered: true}
+	c := InputModal{BaseControl: CreateBaseControl(x, y, width, height, fg, bg), title: title}
+	c.bordered = true
 	c.input = CreateInputField(c.x+2, c.y+3, c.width-2, 2, c.fg, c.bg)
 	c.showHelp = true
 	c.input.bordered = true
@@ -35,25 +37,13 @@ func CreateInputModal(title string, x, y, width, height int, fg, bg termbox.Attr
 	return &c
 }
 
-func (c *InputModal) SetActiveFgColor(fg termbox.Attribute) { c.activeFg = fg }
-func (c *InputModal) SetActiveBgColor(bg termbox.Attribute) { c.activeBg = bg }
-func (c *InputModal) SetActive(a bool)                      { c.active = a }
-func (c *InputModal) IsActive() bool                        { return c.active }
-
-// GetID returns this control's ID
-func (c *InputModal) GetID() string { return c.id }
-
-// SetID sets this control's ID
-func (c *InputModal) SetID(newID string) {
-	c.id = newID
-}
-
 // GetTitle Return the title of the modal
 func (c *InputModal) GetTitle() string { return c.title }
 
 // SetTitle Sets the title of the modal to s
 func (c *InputModal) SetTitle(s string) {
 	c.title = s
+	c.dirty = true
 }
 
 // GetText Return the text of the modal
@@ -62,43 +52,13 @@ func (c *InputModal) GetText() string { return c.text }
 // SetText Set the text of the modal to s
 func (c *InputModal) SetText(s string) {
 	c.text = s
-}
-
-// GetX Return the x position of the modal
-func (c *InputModal) GetX() int { return c.x }
-
-// SetX set the x position of the modal to x
-func (c *InputModal) SetX(x int) {
-	c.x = x
-}
-
-// GetY Return the y position of the modal
-func (c *InputModal) GetY() int { return c.y }
-
-// SetY Set the y position of the modal to y
-func (c *InputModal) SetY(y int) {
-	c.y = y
-}
-
-// GetWidth Return the width of the modal
-func (c *InputModal) GetWidth() int { return c.width }
-
-// SetWidth Set the width of the modal to width
-func (c *InputModal) SetWidth(width int) {
-	c.width = width
-}
-
-// GetHeight Return the height of the modal
-func (c *InputModal) GetHeight() int { return c.height }
-
-// SetHeight Set the height of the modal to height
-func (c *InputModal) SetHeight(height int) {
-	c.height = height
+	c.dirty = true
 }
 
 // SetMultiline returns whether this is a multiline modal
 func (c *InputModal) SetMultiline(m bool) {
 	c.input.multiline = m
+	c.dirty = true
 }
 
 // IsMultiline returns whether this is a multiline modal
@@ -106,24 +66,24 @@ func (c *InputModal) IsMultiline() bool {
 	return c.input.multiline
 }
 
-// IsBordered returns whether this control is bordered or not
-func (c *InputModal) IsBordered() bool {
-	return c.bordered
+// SetBorderStyle sets the style the border is drawn in
+func (c *InputModal) SetBorderStyle(s BorderStyle) {
+	c.borderStyle = s
+	c.input.SetBorderStyle(s)
+	c.dirty = true
 }
 
-// SetBordered sets whether we render a border around the frame
-func (c *InputModal) SetBordered(b bool) {
-	c.bordered = b
+// GetKeymap returns the keymap the modal uses
+func (c *InputModal) GetKeymap() *Keymap {
+	k := ownKeymap(&c.keymap)
+	c.input.SetKeymap(k)
+	return k
 }
 
-// IsTabSkipped returns whether this control has it's tabskip flag set
-func (c *InputModal) IsTabSkipped() bool {
-	return c.tabSkip
-}
-
-// SetTabSkip sets the tabskip flag for this control
-func (c *InputModal) SetTabSkip(b bool) {
-	c.tabSkip = b
+// SetKeymap sets the keymap the modal uses, nil uses the DefaultKeymap
+func (c *InputModal) SetKeymap(k *Keymap) {
+	c.keymap = k
+	c.input.SetKeymap(k)
 }
 
 // HelpIsShown Returns whether the modal is showing it's help text or not
@@ -132,32 +92,19 @@ func (c *InputModal) HelpIsShown() bool { return c.showHelp }
 // ShowHelp Set the "Show Help" flag
 func (c *InputModal) ShowHelp(b bool) {
 	c.showHelp = b
-}
-
-// GetFgColor returns the foreground color
-func (c *InputModal) GetFgColor() termbox.Attribute { return c.fg }
-
-// SetFgColor sets the foreground color
-func (c *InputModal) SetFgColor(fg termbox.Attribute) {
-	c.fg = fg
-}
-
-// GetBgColor returns the background color
-func (c *InputModal) GetBgColor() termbox.Attribute { return c.bg }
-
-// SetBgColor sets the current background color
-func (c *InputModal) SetBgColor(bg termbox.Attribute) {
-	c.bg = bg
+	c.dirty = true
 }
 
 // Show Sets the visibility flag to true
 func (c *InputModal) Show() {
 	c.isVisible = true
+	c.dirty = true
 }
 
 // Hide Sets the visibility flag to false
 func (c *InputModal) Hide() {
 	c.isVisible = false
+	c.dirty = true
 }
 
 // IsVisible returns the isVisible flag
@@ -168,6 +115,7 @@ func (c *InputModal) IsVisible() bool {
 // SetDone Sets the flag that tells whether this modal has completed it's purpose
 func (c *InputModal) SetDone(b bool) {
 	c.isDone = b
+	c.dirty = true
 }
 
 // IsDone Returns the "isDone" flag
@@ -180,17 +128,25 @@ func (c *InputModal) IsAccepted() bool {
 	return c.isAccepted
 }
 
+// SetOnSubmit sets a function that is called with the value when the user accepts the modal
+func (c *InputModal) SetOnSubmit(f func(m *InputModal, value string)) { c.onSubmit = f }
+
+// SetOnCancel sets a function that is called when the user cancels the modal
+func (c *InputModal) SetOnCancel(f func(m *InputModal)) { c.onCancel = f }
+
 // GetValue Return the current value of the input
 func (c *InputModal) GetValue() string { return c.input.GetValue() }
 
 // SetValue Sets the value of the input to s
 func (c *InputModal) SetValue(s string) {
 	c.input.SetValue(s)
+	c.dirty = true
 }
 
 // SetInputWrap sets whether the input field will wrap long text or not
 func (c *InputModal) SetInputWrap(b bool) {
 	c.input.SetWrap(b)
+	c.dirty = true
 }
 
 // Clear Resets all non-positional parameters of the modal
@@ -200,45 +156,76 @@ func (c *InputModal) Clear() {
 	c.input.SetValue("")
 	c.isDone = false
 	c.isVisible = false
+	c.dirty = true
+}
+
+// ApplyTheme sets the colors of the control from the theme t
+func (c *InputModal) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
+	c.borderStyle = t.BorderStyle
+	c.theme = t
+	c.input.ApplyTheme(t)
 }
 
 // HandleEvent Handle the termbox event, return true if it was consumed
 func (c *InputModal) HandleEvent(event termbox.Event) bool {
-	if event.Key == termbox.KeyEnter {
+	switch c.keymap.Match(event, ActionAccept, ActionCancel, ActionSwitchFocus) {
+	case ActionAccept:
 		if !c.input.IsMultiline() || !c.inputSelected {
 			// Done editing
 			c.isDone = true
 			c.isAccepted = true
+			if c.onSubmit != nil {
+				c.onSubmit(c, c.GetValue())
+			}
 		} else {
 			c.input.HandleEvent(event)
 		}
 		return true
-	} else if event.Key == termbox.KeyTab {
+	case ActionSwitchFocus:
 		if c.input.IsMultiline() {
 			c.inputSelected = !c.inputSelected
 		}
-	} else if event.Key == termbox.KeyEsc {
+	case ActionCancel:
 		// Done editing
 		c.isDone = true
 		c.isAccepted = false
+		if c.onCancel != nil {
+			c.onCancel(c)
+		}
 		return true
 	}
 	return c.input.HandleEvent(event)
 }
 
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *InputModal) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *InputModal) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
 // Draw Draw the modal
 func (c *InputModal) Draw() {
 	if c.isVisible {
 		// First blank out the area we'll be putting the modal
 		FillWithChar(' ', c.x, c.y, c.x+c.width, c.y+c.height, c.fg, c.bg)
+		titleFg, titleBg, helpFg, helpBg := c.fg, c.bg, c.fg, c.bg
+		if c.theme != nil {
+			titleFg, titleBg = c.theme.Title.Fg, c.theme.Title.Bg
+			helpFg, helpBg = c.theme.Help.Fg, c.theme.Help.Bg
+		}
 		nextY := c.y + 1
 		// The title
 		if c.title != "" {
-			if len(c.title) > c.width {
-				diff := c.width - len(c.title)
-				DrawStringAtPoint(c.title[:len(c.title)+diff-1], c.x+1, nextY, c.fg, c.bg)
+			if TextWidth(c.title) > c.width {
+				DrawStringAtPoint(TruncateText(c.title, c.width-1), c.x+1, nextY, titleFg, titleBg)
 			} else {
-				DrawStringAtPoint(c.title, c.x+1, nextY, c.fg, c.bg)
+				DrawStringAtPoint(c.title, c.x+1, nextY, titleFg, titleBg)
 			}
 			nextY++
 			FillWithChar('-', c.x+1, nextY, c.x+c.width-1, nextY, c.fg, c.bg)
@@ -248,17 +235,20 @@ func (c *InputModal) Draw() {
 			DrawStringAtPoint(c.text, c.x+1, nextY, c.fg, c.bg)
 			nextY++
 		}
+		// The modal may have been moved or resized since the input was made
+		c.input.SetX(c.x + 2)
 		c.input.SetY(nextY)
+		c.input.SetWidth(c.width - 2)
 		c.input.Draw()
 		nextY += 3
 		if c.showHelp {
 			helpString := " (ENTER) to Accept. (ESC) to Cancel. "
-			helpX := (c.x + c.width - len(helpString)) - 1
-			DrawStringAtPoint(helpString, helpX, nextY, c.fg, c.bg)
+			helpX := (c.x + c.width - TextWidth(helpString)) - 1
+			DrawStringAtPoint(helpString, helpX, nextY, helpFg, helpBg)
 		}
 		if c.bordered {
 			// Now draw the border
-			DrawBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.fg, c.bg)
+			DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", c.fg, c.bg)
 		}
 	}
 }
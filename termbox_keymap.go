@@ -0,0 +1,346 @@
+package termboxUtil
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nsf/termbox-go"
+)
+
+// KeyChord is a key or rune pressed along with any modifiers
+type KeyChord struct {
+	Key termbox.Key
+	Ch  rune
+	Mod termbox.Modifier
+}
+
+// KeyChordFromEvent returns the chord that was pressed for a termbox key event
+func KeyChordFromEvent(event termbox.Event) KeyChord {
+	if event.Ch == ' ' {
+		return KeyChord{Key: termbox.KeySpace, Mod: event.Mod & termbox.ModAlt}
+	}
+	if event.Ch != 0 {
+		return KeyChord{Ch: event.Ch, Mod: event.Mod & termbox.ModAlt}
+	}
+	return KeyChord{Key: event.Key, Mod: event.Mod & termbox.ModAlt}
+}
+
+var keyNames = map[string]termbox.Key{
+	"enter": termbox.KeyEnter, "esc": termbox.KeyEsc, "tab": termbox.KeyTab,
+	"backtab": KeyBackTab, "space": termbox.KeySpace,
+	"backspace": termbox.KeyBackspace2, "delete": termbox.KeyDelete, "insert": termbox.KeyInsert,
+	"home": termbox.KeyHome, "end": termbox.KeyEnd, "pgup": termbox.KeyPgup, "pgdn": termbox.KeyPgdn,
+	"up": termbox.KeyArrowUp, "down": termbox.KeyArrowDown,
+	"left": termbox.KeyArrowLeft, "right": termbox.KeyArrowRight,
+	"f1": termbox.KeyF1, "f2": termbox.KeyF2, "f3": termbox.KeyF3, "f4": termbox.KeyF4,
+	"f5": termbox.KeyF5, "f6": termbox.KeyF6, "f7": termbox.KeyF7, "f8": termbox.KeyF8,
+	"f9": termbox.KeyF9, "f10": termbox.KeyF10, "f11": termbox.KeyF11, "f12": termbox.KeyF12,
+}
+
+// ParseKeyChord parses a chord like "j", "enter", "ctrl-f", "alt-v" or "shift-tab"
+func ParseKeyChord(s string) (KeyChord, error) {
+	var ret KeyChord
+	str := strings.ToLower(s)
+	if strings.HasPrefix(str, "alt-") && len(str) > 4 {
+		ret.Mod = termbox.ModAlt
+		s, str = s[4:], str[4:]
+	}
+	if str == "shift-tab" {
+		str = "backtab"
+	}
+	if k, ok := keyNames[str]; ok {
+		ret.Key = k
+		return ret, nil
+	}
+	if strings.HasPrefix(str, "ctrl-") && len(str) == 6 && str[5] >= 'a' && str[5] <= 'z' {
+		ret.Key = termbox.KeyCtrlA + termbox.Key(str[5]-'a')
+		return ret, nil
+	}
+	if utf8.RuneCountInString(s) == 1 {
+		ret.Ch, _ = utf8.DecodeRuneInString(s)
+		return ret, nil
+	}
+	return ret, errors.New("Unknown key: " + s)
+}
+
+// MustParseKeyChord is ParseKeyChord for chords that are known to be good,
+// it panics if s can't be parsed
+func MustParseKeyChord(s string) KeyChord {
+	k, err := ParseKeyChord(s)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// String returns the chord in the form ParseKeyChord reads
+func (k KeyChord) String() string {
+	var ret string
+	if k.Mod&termbox.ModAlt != 0 {
+		ret = "alt-"
+	}
+	if k.Ch != 0 {
+		return ret + string(k.Ch)
+	}
+	for n, v := range keyNames {
+		if v == k.Key {
+			return ret + n
+		}
+	}
+	if k.Key >= termbox.KeyCtrlA && k.Key <= termbox.KeyCtrlZ {
+		return ret + "ctrl-" + string(rune('a'+k.Key-termbox.KeyCtrlA))
+	}
+	return ret + "unknown"
+}
+
+// Action is something a key can be bound to. Controls each support their
+// own set of actions, see the Action constants.
+type Action string
+
+// The actions the built in controls understand
+const (
+	// ActionSelectNext and ActionSelectPrev move a selection (Menu, DropMenu)
+	ActionSelectNext Action = "select-next"
+	ActionSelectPrev Action = "select-prev"
+	// ActionPageUp and ActionPageDown move a selection by a page (Menu)
+	ActionPageUp   Action = "page-up"
+	ActionPageDown Action = "page-down"
+	// ActionSelectFirst and ActionSelectLast jump to the ends (Menu)
+	ActionSelectFirst Action = "select-first"
+	ActionSelectLast  Action = "select-last"
+	// ActionAccept finishes a control with a yes (AlertModal, InputModal, Menu)
+	ActionAccept Action = "accept"
+	// ActionCancel finishes a control with a no (InputModal)
+	ActionCancel Action = "cancel"
+	// ActionActivate presses, toggles or chooses (Button, Checkbox, Menu)
+	ActionActivate Action = "activate"
+	// ActionYes and ActionNo answer a question (ConfirmModal)
+	ActionYes Action = "yes"
+	ActionNo  Action = "no"
+	// ActionSwitchFocus moves between the parts of a control (InputModal)
+	ActionSwitchFocus Action = "switch-focus"
+	// The editing actions (InputField)
+	ActionCursorLeft    Action = "cursor-left"
+	ActionCursorRight   Action = "cursor-right"
+	ActionCursorStart   Action = "cursor-start"
+	ActionCursorEnd     Action = "cursor-end"
+	ActionDeleteBack    Action = "delete-back"
+	ActionDeleteForward Action = "delete-forward"
+	ActionClearToStart  Action = "clear-to-start"
+	ActionClearToEnd    Action = "clear-to-end"
+	ActionNewline       Action = "newline"
+)
+
+// Keymap maps key chords to actions. A chord can be bound to more than one
+// action, a control uses the first one that it supports, so the same key
+// can mean different things to different controls.
+type Keymap struct {
+	name     string
+	bindings map[KeyChord][]Action
+	// builtin is set for the keymaps every control shares
+	builtin bool
+}
+
+// CreateKeymap creates an empty keymap named name
+func CreateKeymap(name string) *Keymap {
+	return &Keymap{name: name, bindings: make(map[KeyChord][]Action)}
+}
+
+// GetName returns the keymap's name
+func (k *Keymap) GetName() string { return k.name }
+
+// Clone returns a copy of the keymap named name, which can be changed
+// without changing k
+func (k *Keymap) Clone(name string) *Keymap {
+	ret := CreateKeymap(name)
+	for c, a := range k.bindings {
+		ret.bindings[c] = append([]Action{}, a...)
+	}
+	return ret
+}
+
+// Bind binds the chord c to the action a, after any actions it's already bound to
+func (k *Keymap) Bind(c KeyChord, a Action) {
+	for _, v := range k.bindings[c] {
+		if v == a {
+			return
+		}
+	}
+	k.bindings[c] = append(k.bindings[c], a)
+}
+
+// BindKeys binds every one of the chords (see ParseKeyChord) to the action a
+func (k *Keymap) BindKeys(a Action, chords ...string) error {
+	for _, s := range chords {
+		c, err := ParseKeyChord(s)
+		if err != nil {
+			return err
+		}
+		k.Bind(c, a)
+	}
+	return nil
+}
+
+// Unbind removes the action a from the chord c
+func (k *Keymap) Unbind(c KeyChord, a Action) {
+	var keep []Action
+	for _, v := range k.bindings[c] {
+		if v != a {
+			keep = append(keep, v)
+		}
+	}
+	if len(keep) == 0 {
+		delete(k.bindings, c)
+		return
+	}
+	k.bindings[c] = keep
+}
+
+// Rebind makes the chords the only ones bound to the action a
+func (k *Keymap) Rebind(a Action, chords ...KeyChord) {
+	for _, c := range k.GetChords(a) {
+		k.Unbind(c, a)
+	}
+	for _, c := range chords {
+		k.Bind(c, a)
+	}
+}
+
+// GetActions returns the actions the chord c is bound to, in order
+func (k *Keymap) GetActions(c KeyChord) []Action { return k.bindings[c] }
+
+// GetChords returns the chords that are bound to the action a
+func (k *Keymap) GetChords(a Action) []KeyChord {
+	var ret []KeyChord
+	for c, acts := range k.bindings {
+		for _, v := range acts {
+			if v == a {
+				ret = append(ret, c)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// Match returns the first action bound to the key pressed in event
+// that is one of supported, or "" if there isn't one
+func (k *Keymap) Match(event termbox.Event, supported ...Action) Action {
+	if k == nil {
+		k = DefaultKeymap
+	}
+	if event.Type != termbox.EventKey {
+		return ""
+	}
+	for _, a := range k.bindings[KeyChordFromEvent(event)] {
+		for _, s := range supported {
+			if a == s {
+				return a
+			}
+		}
+	}
+	return ""
+}
+
+// ownKeymap returns the keymap in *k for a control's GetKeymap. A control
+// using a built in keymap (or the DefaultKeymap, for nil) gets its own
+// copy of it first, so changing the keymap returned only changes that
+// control and not every other one using the built in keymap.
+func ownKeymap(k **Keymap) *Keymap {
+	if *k == nil {
+		*k = DefaultKeymap
+	}
+	if (*k).builtin {
+		*k = (*k).Clone((*k).name)
+	}
+	return *k
+}
+
+// mustBind is BindKeys for the built in keymaps
+func (k *Keymap) mustBind(a Action, chords ...string) {
+	if err := k.BindKeys(a, chords...); err != nil {
+		panic(err)
+	}
+}
+
+// DefaultKeymap is used by any control that hasn't had a keymap set.
+// Changing it changes every control that shares it, a control's
+// GetKeymap gives the control its own copy to change instead.
+var DefaultKeymap = createDefaultKeymap()
+
+// VimKeymap is the DefaultKeymap plus j/k, Ctrl-F/Ctrl-B and g/G in lists
+var VimKeymap = createVimKeymap()
+
+// EmacsKeymap is the DefaultKeymap plus Emacs style movement and editing
+// keys. It doesn't bind any Alt chords (like Alt-V for page up): an App
+// reads the terminal in termbox.InputEsc mode, where Alt chords come in as
+// Esc and then the key. They can be bound on a copy of it for an app run
+// in termbox.InputAlt mode, which gives up the Esc key instead.
+var EmacsKeymap = createEmacsKeymap()
+
+func createDefaultKeymap() *Keymap {
+	k := CreateKeymap("default")
+	k.mustBind(ActionSelectNext, "down")
+	k.mustBind(ActionSelectPrev, "up")
+	k.mustBind(ActionPageUp, "left", "pgup")
+	k.mustBind(ActionPageDown, "right", "pgdn")
+	k.mustBind(ActionSelectFirst, "home")
+	k.mustBind(ActionSelectLast, "end")
+	k.mustBind(ActionAccept, "enter")
+	k.mustBind(ActionCancel, "esc")
+	k.mustBind(ActionNewline, "enter")
+	k.mustBind(ActionActivate, "enter", "space")
+	k.mustBind(ActionYes, "y", "Y")
+	k.mustBind(ActionNo, "n", "N")
+	k.mustBind(ActionSwitchFocus, "tab")
+	k.mustBind(ActionCursorLeft, "left")
+	k.mustBind(ActionCursorRight, "right")
+	k.mustBind(ActionCursorStart, "home")
+	k.mustBind(ActionCursorEnd, "end")
+	k.Bind(KeyChord{Key: termbox.KeyBackspace}, ActionDeleteBack)
+	k.mustBind(ActionDeleteBack, "backspace")
+	k.mustBind(ActionDeleteForward, "delete")
+	k.mustBind(ActionClearToStart, "ctrl-u")
+	k.builtin = true
+	return k
+}
+
+func createVimKeymap() *Keymap {
+	k := createDefaultKeymap().Clone("vim")
+	k.mustBind(ActionSelectNext, "j")
+	k.mustBind(ActionSelectPrev, "k")
+	k.mustBind(ActionPageDown, "ctrl-f")
+	k.mustBind(ActionPageUp, "ctrl-b")
+	k.mustBind(ActionSelectFirst, "g")
+	k.mustBind(ActionSelectLast, "G")
+	k.builtin = true
+	return k
+}
+
+func createEmacsKeymap() *Keymap {
+	k := createDefaultKeymap().Clone("emacs")
+	k.mustBind(ActionSelectNext, "ctrl-n")
+	k.mustBind(ActionSelectPrev, "ctrl-p")
+	k.mustBind(ActionPageDown, "ctrl-v")
+	k.mustBind(ActionCancel, "ctrl-g")
+	k.mustBind(ActionCursorLeft, "ctrl-b")
+	k.mustBind(ActionCursorRight, "ctrl-f")
+	k.mustBind(ActionCursorStart, "ctrl-a")
+	k.mustBind(ActionCursorEnd, "ctrl-e")
+	k.mustBind(ActionDeleteForward, "ctrl-d")
+	k.mustBind(ActionClearToEnd, "ctrl-k")
+	k.builtin = true
+	return k
+}
+
+// GetKeymapByName returns a copy of the built in keymap with the name n,
+// which can be changed without changing the built in one
+func GetKeymapByName(n string) (*Keymap, bool) {
+	for _, k := range []*Keymap{DefaultKeymap, VimKeymap, EmacsKeymap} {
+		if k.name == n {
+			return k.Clone(n), true
+		}
+	}
+	return nil, false
+}
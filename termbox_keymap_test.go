@@ -0,0 +1,68 @@
+package termboxUtil_test
+
+import (
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func createTestMenu() *termboxUtil.Menu {
+	return termboxUtil.CreateMenu("", []string{"one", "two", "three"}, 0, 0, 10, 5, termbox.ColorWhite, termbox.ColorBlack)
+}
+
+func TestGetKeymapDoesntShareBuiltins(t *testing.T) {
+	s := screentest.CreateScreen(10, 5)
+	one, two := createTestMenu(), createTestMenu()
+	x := termboxUtil.MustParseKeyChord("x")
+	one.GetKeymap().Bind(x, termboxUtil.ActionSelectNext)
+	if acts := termboxUtil.DefaultKeymap.GetActions(x); len(acts) != 0 {
+		t.Errorf("expected binding a menu's key not to change the DefaultKeymap, got %v", acts)
+	}
+	s.Send(one, screentest.Rune('x'))
+	s.Send(two, screentest.Rune('x'))
+	if one.GetSelectedIndex() != 1 {
+		t.Errorf("expected x to move the first menu down, it's on %d", one.GetSelectedIndex())
+	}
+	if two.GetSelectedIndex() != 0 {
+		t.Errorf("expected x not to move the second menu, it's on %d", two.GetSelectedIndex())
+	}
+
+	one.EnableVimMode()
+	j := termboxUtil.MustParseKeyChord("j")
+	one.GetKeymap().Unbind(j, termboxUtil.ActionSelectNext)
+	if len(termboxUtil.VimKeymap.GetActions(j)) == 0 {
+		t.Error("expected unbinding a menu's key not to change the VimKeymap")
+	}
+}
+
+func TestGetKeymapByNameCopies(t *testing.T) {
+	k, ok := termboxUtil.GetKeymapByName("emacs")
+	if !ok {
+		t.Fatal("expected the emacs keymap to be found")
+	}
+	if k == termboxUtil.EmacsKeymap {
+		t.Fatal("expected a copy of the emacs keymap")
+	}
+	ctrlN := termboxUtil.MustParseKeyChord("ctrl-n")
+	k.Unbind(ctrlN, termboxUtil.ActionSelectNext)
+	if len(termboxUtil.EmacsKeymap.GetActions(ctrlN)) == 0 {
+		t.Error("expected changing the copy not to change the EmacsKeymap")
+	}
+}
+
+func TestEmacsKeymap(t *testing.T) {
+	s := screentest.CreateScreen(10, 5)
+	m := createTestMenu()
+	m.SetKeymap(termboxUtil.EmacsKeymap)
+	s.Send(m, screentest.Key(termbox.KeyCtrlN), screentest.Key(termbox.KeyCtrlN), screentest.Key(termbox.KeyCtrlP))
+	if m.GetSelectedIndex() != 1 {
+		t.Errorf("expected Ctrl-N, Ctrl-N, Ctrl-P to end up on 1, got %d", m.GetSelectedIndex())
+	}
+	for _, c := range []string{"alt-v", "alt-<", "alt->"} {
+		if acts := termboxUtil.EmacsKeymap.GetActions(termboxUtil.MustParseKeyChord(c)); len(acts) != 0 {
+			t.Errorf("expected %s not to be bound, an App never sees it", c)
+		}
+	}
+}
@@ -0,0 +1,598 @@
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+/* Layout Containers */
+// VBox and HBox (both Boxes) stack their controls down or across, Grid
+// puts them in rows and columns. They size and position their controls
+// themselves, so positions given to the controls are overwritten. Like
+// Frame, a control's position is relative to the container.
+
+// SizeMode is how a Size is worked out
+type SizeMode int
+
+const (
+	// SizeAuto keeps the control's own size
+	SizeAuto SizeMode = iota
+	// SizeFixed is a fixed number of cells
+	SizeFixed
+	// SizePercent is a percentage of the container
+	SizePercent
+	// SizeFlex shares whatever space is left by weight
+	SizeFlex
+)
+
+// Size is the size of a control (or grid row or column) along the
+// direction a container lays things out in
+type Size struct {
+	Mode  SizeMode
+	Value int
+	// Min and Max limit the size, 0 means no limit
+	Min, Max int
+}
+
+// Fixed is a size of n cells
+func Fixed(n int) Size { return Size{Mode: SizeFixed, Value: n} }
+
+// Percent is a size of pct percent of the container
+func Percent(pct int) Size { return Size{Mode: SizePercent, Value: pct} }
+
+// Flex is a share of the space left over, weighted by weight
+func Flex(weight int) Size { return Size{Mode: SizeFlex, Value: weight} }
+
+// Auto is whatever size the control already is
+func Auto() Size { return Size{Mode: SizeAuto} }
+
+// WithMin returns the size limited to at least n cells
+func (s Size) WithMin(n int) Size {
+	s.Min = n
+	return s
+}
+
+// WithMax returns the size limited to at most n cells
+func (s Size) WithMax(n int) Size {
+	s.Max = n
+	return s
+}
+
+// clamp limits n to the size's Min and Max
+func (s Size) clamp(n int) int {
+	if s.Max > 0 && n > s.Max {
+		n = s.Max
+	}
+	if n < s.Min {
+		n = s.Min
+	}
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+// Padding is the space left inside the edges of a container
+type Padding struct {
+	Top, Right, Bottom, Left int
+}
+
+// UniformPadding is n cells of padding on every side
+func UniformPadding(n int) Padding { return Padding{n, n, n, n} }
+
+// layoutTracks works out the offset and length of each of sizes laid out
+// one after another in length cells, with spacing cells between each.
+// auto gives the current size of anything that's SizeAuto.
+func layoutTracks(sizes []Size, auto func(int) int, length, spacing int) ([]int, []int) {
+	lengths := make([]int, len(sizes))
+	offsets := make([]int, len(sizes))
+	if len(sizes) == 0 {
+		return offsets, lengths
+	}
+	avail := length - spacing*(len(sizes)-1)
+	used := 0
+	flexed := make([]bool, len(sizes))
+	for k, s := range sizes {
+		switch s.Mode {
+		case SizeFixed:
+			lengths[k] = s.clamp(s.Value)
+		case SizePercent:
+			lengths[k] = s.clamp(avail * s.Value / 100)
+		case SizeFlex:
+			flexed[k] = true
+			continue
+		default:
+			lengths[k] = s.clamp(auto(k))
+		}
+		used += lengths[k]
+	}
+	// Share out what's left by weight. Anything that hits its min or max
+	// keeps that size and the rest is shared out again among the others.
+	for {
+		weight, left, last := 0, avail-used, -1
+		for k, f := range flexed {
+			if f {
+				weight += sizes[k].Value
+				last = k
+			}
+		}
+		if last == -1 {
+			break
+		}
+		if weight <= 0 {
+			weight = 1
+		}
+		clamped := false
+		shared := 0
+		for k, f := range flexed {
+			if !f {
+				continue
+			}
+			n := left * sizes[k].Value / weight
+			if k == last {
+				n = left - shared
+			}
+			if c := sizes[k].clamp(n); c != n {
+				lengths[k], flexed[k] = c, false
+				used += c
+				clamped = true
+				break
+			}
+			lengths[k] = n
+			shared += n
+		}
+		if !clamped {
+			break
+		}
+	}
+	pos := 0
+	for k := range sizes {
+		offsets[k] = pos
+		pos += lengths[k] + spacing
+	}
+	return offsets, lengths
+}
+
+// borderCells is how many cells past its width (and height) t takes up.
+// Borders are drawn at x+width and y+height, so a bordered control takes
+// up one cell more each way than its size.
+func borderCells(t Control) int {
+	if t.IsBordered() {
+		return 1
+	}
+	return 0
+}
+
+// layoutBase is everything the layout containers have in common
+type layoutBase struct {
+	BaseControl
+	theme    *Theme
+	tabIdx   int
+	padding  Padding
+	spacing  int
+	controls []Control
+	mouse    mouseRouter
+	// self is the container this is part of, doLayout lays it out
+	self     Control
+	doLayout func()
+}
+
+// SetWidth sets the width of the container and lays it out again
+func (c *layoutBase) SetWidth(w int) {
+	c.BaseControl.SetWidth(w)
+	c.doLayout()
+}
+
+// SetHeight sets the height of the container and lays it out again
+func (c *layoutBase) SetHeight(h int) {
+	c.BaseControl.SetHeight(h)
+	c.doLayout()
+}
+
+// SetActive sets whether the container is active, the control at
+// the tab index is active along with it
+func (c *layoutBase) SetActive(a bool) {
+	c.BaseControl.SetActive(a)
+	for idx := range c.controls {
+		c.controls[idx].SetActive(a && idx == c.tabIdx)
+	}
+}
+
+// SetBordered sets whether the container has a border and lays it out again
+func (c *layoutBase) SetBordered(b bool) {
+	c.BaseControl.SetBordered(b)
+	c.doLayout()
+}
+
+// GetPadding returns the space left inside the edges of the container
+func (c *layoutBase) GetPadding() Padding { return c.padding }
+
+// SetPadding sets the space left inside the edges of the container
+func (c *layoutBase) SetPadding(p Padding) {
+	c.padding = p
+	c.doLayout()
+	c.dirty = true
+}
+
+// GetSpacing returns the space left between controls
+func (c *layoutBase) GetSpacing() int { return c.spacing }
+
+// SetSpacing sets the space left between controls
+func (c *layoutBase) SetSpacing(s int) {
+	c.spacing = s
+	c.doLayout()
+	c.dirty = true
+}
+
+// GetControls returns a slice of all controls
+func (c *layoutBase) GetControls() []Control { return c.controls }
+
+// GetActiveControl returns the control at the tab index
+func (c *layoutBase) GetActiveControl() Control {
+	if c.tabIdx < len(c.controls) {
+		return c.controls[c.tabIdx]
+	}
+	return nil
+}
+
+// SetActiveControl makes t the active control, returning false if t isn't in the container
+func (c *layoutBase) SetActiveControl(t Control) bool {
+	for idx := range c.controls {
+		if c.controls[idx] == t {
+			c.tabIdx = idx
+			c.SetActive(c.active)
+			return true
+		}
+	}
+	return false
+}
+
+// addControl adds t to the container, applying the container's theme to it
+func (c *layoutBase) addControl(t Control) {
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
+	c.controls = append(c.controls, t)
+	c.dirty = true
+}
+
+// IndexOf returns the index of the control t in the container, or -1
+func (c *layoutBase) IndexOf(t Control) int { return indexOfControl(c.controls, t) }
+
+// removeControl removes t from the container, returning
+// its index or -1 if it isn't in the container
+func (c *layoutBase) removeControl(t Control) int {
+	idx := c.IndexOf(t)
+	if idx == -1 {
+		return -1
+	}
+	c.dirty = true
+	c.controls, c.tabIdx = removeControlAt(c.controls, c.tabIdx, idx)
+	if c.mouse.capture == t {
+		c.mouse.capture = nil
+	}
+	t.SetActive(false)
+	c.SetActive(c.active)
+	return idx
+}
+
+// ReplaceControl puts the control t in the container in place of old,
+// in the same spot and the same size. It returns false if old isn't in
+// the container.
+func (c *layoutBase) ReplaceControl(old, t Control) bool {
+	idx := c.IndexOf(old)
+	if idx == -1 {
+		return false
+	}
+	c.dirty = true
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
+	c.controls[idx] = t
+	if c.mouse.capture == old {
+		c.mouse.capture = nil
+	}
+	old.SetActive(false)
+	c.SetActive(c.active)
+	c.doLayout()
+	return true
+}
+
+// SetTheme applies the theme t to the container and everything in it.
+// Controls added later get the theme as well.
+func (c *layoutBase) SetTheme(t *Theme) { ApplyTheme(c.self, t) }
+
+// GetTheme returns the theme applied to the container, or nil
+func (c *layoutBase) GetTheme() *Theme { return c.theme }
+
+// ApplyTheme sets the colors of the container and everything in it from the theme t
+func (c *layoutBase) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
+	c.borderStyle = t.BorderStyle
+	c.theme = t
+	for _, v := range c.controls {
+		ApplyTheme(v, t)
+	}
+}
+
+// GetInnerRect returns the area inside the border and padding that
+// controls are laid out in, relative to the container. The border is
+// drawn at x+width and y+height, like every other control's.
+func (c *layoutBase) GetInnerRect() (int, int, int, int) {
+	x, y := c.padding.Left, c.padding.Top
+	w := c.width - c.padding.Left - c.padding.Right
+	h := c.height - c.padding.Top - c.padding.Bottom
+	if c.bordered {
+		x, y, w, h = x+1, y+1, w-1, h-1
+	}
+	return x, y, w, h
+}
+
+// HandleEvent accepts the termbox event and returns whether it was consumed
+func (c *layoutBase) HandleEvent(event termbox.Event) bool {
+	// Focus is handled for the container and everything in it as a whole
+	return CreateFocusManager(c.self).HandleEvent(event)
+}
+
+// HandleMouse sends the mouse event on to the control under it,
+// giving that control focus if it was clicked
+func (c *layoutBase) HandleMouse(ev MouseEvent) bool {
+	c.doLayout()
+	idx, ret := c.mouse.route(c.controls, ev, 0, 0)
+	if idx >= 0 && !c.controls[idx].IsTabSkipped() {
+		c.SetActiveControl(c.controls[idx])
+	}
+	return ret
+}
+
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *layoutBase) DrawToStrings() []string {
+	return DrawControlToBuffer(c.self).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *layoutBase) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c.self).GetCells()
+}
+
+// Draw lays out the container and draws it and its controls
+func (c *layoutBase) Draw() {
+	c.doLayout()
+	if c.bordered {
+		borderFg, borderBg := c.fg, c.bg
+		if c.theme != nil {
+			borderFg, borderBg = c.theme.Border.Fg, c.theme.Border.Bg
+		}
+		if c.active {
+			borderFg, borderBg = c.activeFg, c.activeBg
+		}
+		DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", borderFg, borderBg)
+	}
+	ix, iy, iw, ih := c.GetInnerRect()
+	for _, t := range c.controls {
+		ctlX, ctlY := t.GetX(), t.GetY()
+		t.SetX(c.x + ctlX)
+		t.SetY(c.y + ctlY)
+		DrawClipped(t, c.x+ix, c.y+iy, iw, ih)
+		t.SetX(ctlX)
+		t.SetY(ctlY)
+	}
+}
+
+// Box lays its controls out one after another, either down (a VBox)
+// or across (an HBox). Each control gets a Size along the box and
+// fills the box the other way.
+type Box struct {
+	layoutBase
+	vertical bool
+	sizes    []Size
+}
+
+// CreateVBox creates a box at x, y that is w by h that stacks its controls down
+func CreateVBox(x, y, w, h int, fg, bg termbox.Attribute) *Box {
+	return createBox(x, y, w, h, fg, bg, true)
+}
+
+// CreateHBox creates a box at x, y that is w by h that lines its controls up across
+func CreateHBox(x, y, w, h int, fg, bg termbox.Attribute) *Box {
+	return createBox(x, y, w, h, fg, bg, false)
+}
+
+func createBox(x, y, w, h int, fg, bg termbox.Attribute, vertical bool) *Box {
+	c := Box{vertical: vertical}
+	c.BaseControl = CreateBaseControl(x, y, w, h, fg, bg)
+	c.self, c.doLayout = &c, c.Layout
+	return &c
+}
+
+// IsVertical returns whether this is a VBox
+func (c *Box) IsVertical() bool { return c.vertical }
+
+// AddControl adds the control t to the end of the box with the size s
+func (c *Box) AddControl(t Control, s Size) {
+	c.addControl(t)
+	c.sizes = append(c.sizes, s)
+	c.Layout()
+}
+
+// InsertControl inserts the control t into the box at index idx. It's Auto
+// sized, use SetSize to change that.
+func (c *Box) InsertControl(idx int, t Control) {
+	c.InsertControlSize(idx, t, Auto())
+}
+
+// InsertControlSize inserts the control t into the box at index idx with the size s
+func (c *Box) InsertControlSize(idx int, t Control, s Size) {
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
+	c.controls, c.tabIdx = insertControlAt(c.controls, c.tabIdx, idx, t)
+	idx = c.IndexOf(t)
+	c.sizes = append(c.sizes[:idx], append([]Size{s}, c.sizes[idx:]...)...)
+	c.SetActive(c.active)
+	c.Layout()
+}
+
+// RemoveControl removes the control t from the box,
+// returning false if it isn't in the box
+func (c *Box) RemoveControl(t Control) bool {
+	idx := c.removeControl(t)
+	if idx == -1 {
+		return false
+	}
+	c.sizes = append(c.sizes[:idx], c.sizes[idx+1:]...)
+	c.Layout()
+	return true
+}
+
+// GetSize returns the size of the control t in the box
+func (c *Box) GetSize(t Control) Size {
+	for idx := range c.controls {
+		if c.controls[idx] == t {
+			return c.sizes[idx]
+		}
+	}
+	return Size{}
+}
+
+// SetSize sets the size of the control t in the box
+func (c *Box) SetSize(t Control, s Size) {
+	for idx := range c.controls {
+		if c.controls[idx] == t {
+			c.sizes[idx] = s
+		}
+	}
+	c.Layout()
+	c.dirty = true
+}
+
+// Layout sizes and positions the controls in the box. Bordered controls
+// are made a cell smaller each way, so their borders fit in their space.
+func (c *Box) Layout() {
+	ix, iy, iw, ih := c.GetInnerRect()
+	length, auto := iw, func(k int) int { return c.controls[k].GetWidth() + borderCells(c.controls[k]) }
+	if c.vertical {
+		length, auto = ih, func(k int) int { return c.controls[k].GetHeight() + borderCells(c.controls[k]) }
+	}
+	offsets, lengths := layoutTracks(c.sizes, auto, length, c.spacing)
+	for k, t := range c.controls {
+		b := borderCells(t)
+		if c.vertical {
+			t.SetX(ix)
+			t.SetY(iy + offsets[k])
+			t.SetWidth(maxInt(iw-b, 0))
+			t.SetHeight(maxInt(lengths[k]-b, 0))
+		} else {
+			t.SetX(ix + offsets[k])
+			t.SetY(iy)
+			t.SetWidth(maxInt(lengths[k]-b, 0))
+			t.SetHeight(maxInt(ih-b, 0))
+		}
+	}
+}
+
+// Grid lays its controls out in rows and columns. Each row and column
+// has a Size and a control can span more than one of them.
+type Grid struct {
+	layoutBase
+	rows, cols []Size
+	cells      []gridCell
+}
+
+// gridCell is where a control is in the grid
+type gridCell struct {
+	row, col, rowSpan, colSpan int
+}
+
+// CreateGrid creates a grid at x, y that is w by h with the rows and columns given
+func CreateGrid(x, y, w, h int, rows, cols []Size, fg, bg termbox.Attribute) *Grid {
+	c := Grid{rows: rows, cols: cols}
+	c.BaseControl = CreateBaseControl(x, y, w, h, fg, bg)
+	c.self, c.doLayout = &c, c.Layout
+	return &c
+}
+
+// GetRows returns the sizes of the rows
+func (c *Grid) GetRows() []Size { return c.rows }
+
+// SetRows sets the sizes of the rows
+func (c *Grid) SetRows(rows ...Size) {
+	c.rows = rows
+	c.Layout()
+	c.dirty = true
+}
+
+// GetColumns returns the sizes of the columns
+func (c *Grid) GetColumns() []Size { return c.cols }
+
+// SetColumns sets the sizes of the columns
+func (c *Grid) SetColumns(cols ...Size) {
+	c.cols = cols
+	c.Layout()
+	c.dirty = true
+}
+
+// AddControl adds the control t to the grid in row, col
+func (c *Grid) AddControl(t Control, row, col int) {
+	c.AddControlSpan(t, row, col, 1, 1)
+}
+
+// AddControlSpan adds the control t to the grid at row, col covering
+// rowSpan rows and colSpan columns. Spans less than 1 are taken as 1.
+func (c *Grid) AddControlSpan(t Control, row, col, rowSpan, colSpan int) {
+	rowSpan, colSpan = maxInt(rowSpan, 1), maxInt(colSpan, 1)
+	c.addControl(t)
+	c.cells = append(c.cells, gridCell{row, col, rowSpan, colSpan})
+	c.Layout()
+}
+
+// RemoveControl removes the control t from the grid,
+// returning false if it isn't in the grid
+func (c *Grid) RemoveControl(t Control) bool {
+	idx := c.removeControl(t)
+	if idx == -1 {
+		return false
+	}
+	c.cells = append(c.cells[:idx], c.cells[idx+1:]...)
+	c.Layout()
+	return true
+}
+
+// Layout sizes and positions the controls in the grid. Bordered controls
+// are made a cell smaller each way, so their borders fit in their cells.
+func (c *Grid) Layout() {
+	ix, iy, iw, ih := c.GetInnerRect()
+	// Auto rows and columns are as big as the biggest control that's only in them
+	autoSize := func(k int, isRow bool) int {
+		var ret int
+		for idx, cell := range c.cells {
+			t := c.controls[idx]
+			if isRow && cell.row == k && cell.rowSpan == 1 {
+				ret = maxInt(ret, t.GetHeight()+borderCells(t))
+			} else if !isRow && cell.col == k && cell.colSpan == 1 {
+				ret = maxInt(ret, t.GetWidth()+borderCells(t))
+			}
+		}
+		return ret
+	}
+	rowOffsets, rowLengths := layoutTracks(c.rows, func(k int) int { return autoSize(k, true) }, ih, c.spacing)
+	colOffsets, colLengths := layoutTracks(c.cols, func(k int) int { return autoSize(k, false) }, iw, c.spacing)
+	span := func(offsets, lengths []int, start, count int) (int, int) {
+		if start < 0 || start >= len(offsets) {
+			return 0, 0
+		}
+		end := start + count - 1
+		if end >= len(offsets) {
+			end = len(offsets) - 1
+		}
+		return offsets[start], offsets[end] + lengths[end] - offsets[start]
+	}
+	for idx, t := range c.controls {
+		cell := c.cells[idx]
+		y, h := span(rowOffsets, rowLengths, cell.row, cell.rowSpan)
+		x, w := span(colOffsets, colLengths, cell.col, cell.colSpan)
+		b := borderCells(t)
+		t.SetX(ix + x)
+		t.SetY(iy + y)
+		t.SetWidth(maxInt(w-b, 0))
+		t.SetHeight(maxInt(h-b, 0))
+	}
+}
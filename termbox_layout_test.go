@@ -0,0 +1,62 @@
+package termboxUtil_test
+
+import (
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func createBorderedLabel(txt string) *termboxUtil.Label {
+	l := termboxUtil.CreateLabel(txt, 0, 0, 0, 0, termbox.ColorWhite, termbox.ColorBlack)
+	l.SetBordered(true)
+	return l
+}
+
+func TestBoxFitsBorderedChildren(t *testing.T) {
+	s := screentest.CreateScreen(12, 9)
+	box := termboxUtil.CreateVBox(0, 0, 10, 8, termbox.ColorWhite, termbox.ColorBlack)
+	box.SetBordered(true)
+	box.AddControl(createBorderedLabel("one"), termboxUtil.Flex(1))
+	box.AddControl(createBorderedLabel("two"), termboxUtil.Flex(1))
+	s.Draw(box)
+	s.AssertRegion(t, 0, 0, []string{
+		"╔═════════╗ ",
+		"║╔═══════╗║ ",
+		"║║one    ║║ ",
+		"║╚═══════╝║ ",
+		"║╔═══════╗║ ",
+		"║║two    ║║ ",
+		"║║       ║║ ",
+		"║╚═══════╝║ ",
+		"╚═════════╝ ",
+	})
+}
+
+func TestGridFitsBorderedChildren(t *testing.T) {
+	s := screentest.CreateScreen(11, 5)
+	cols := []termboxUtil.Size{termboxUtil.Flex(1), termboxUtil.Flex(1)}
+	grid := termboxUtil.CreateGrid(0, 0, 10, 4, []termboxUtil.Size{termboxUtil.Flex(1)}, cols, termbox.ColorWhite, termbox.ColorBlack)
+	grid.AddControl(createBorderedLabel("a"), 0, 0)
+	grid.AddControl(createBorderedLabel("b"), 0, 1)
+	s.Draw(grid)
+	s.AssertRegion(t, 0, 0, []string{
+		"╔═══╗╔═══╗ ",
+		"║a  ║║b  ║ ",
+		"║   ║║   ║ ",
+		"╚═══╝╚═══╝ ",
+		"           ",
+	})
+}
+
+func TestGridSpanLessThanOne(t *testing.T) {
+	sizes := []termboxUtil.Size{termboxUtil.Fixed(3), termboxUtil.Fixed(3)}
+	grid := termboxUtil.CreateGrid(0, 0, 6, 6, sizes, sizes, termbox.ColorWhite, termbox.ColorBlack)
+	lbl := termboxUtil.CreateLabel("a", 0, 0, 0, 0, termbox.ColorWhite, termbox.ColorBlack)
+	grid.AddControlSpan(lbl, 0, 0, 0, -2)
+	if lbl.GetWidth() != 3 || lbl.GetHeight() != 3 {
+		t.Errorf("expected a span of less than 1 to cover one cell, got %dx%d", lbl.GetWidth(), lbl.GetHeight())
+	}
+	screentest.CreateScreen(7, 7).Draw(grid)
+}
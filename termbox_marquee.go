@@ -0,0 +1,137 @@
+package termboxUtil
+
+import (
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Marquee is a line of text that scrolls along when it's too wide to fit.
+// It's animated by an App's ticks (or TickControls).
+type Marquee struct {
+	BaseControl
+	text     string
+	gap      int
+	offset   int
+	interval time.Duration
+	last     time.Time
+}
+
+// CreateMarquee creates a marquee showing text at x, y that is w wide.
+// It moves along a cell every 150ms, with a gap of 3 cells between the
+// end of the text and the start of it coming round again.
+func CreateMarquee(text string, x, y, w int, fg, bg termbox.Attribute) *Marquee {
+	c := Marquee{BaseControl: CreateBaseControl(x, y, w, 1, fg, bg),
+		text: text, gap: 3, interval: 150 * time.Millisecond,
+	}
+	c.tabSkip = true
+	return &c
+}
+
+// GetText returns the marquee's text
+func (c *Marquee) GetText() string { return c.text }
+
+// SetText sets the marquee's text and starts it from the beginning
+func (c *Marquee) SetText(s string) {
+	c.text = s
+	c.offset = 0
+	c.dirty = true
+}
+
+// GetGap returns how many cells are left between the end of the text and
+// the start of it coming round again
+func (c *Marquee) GetGap() int { return c.gap }
+
+// SetGap sets how many cells are left between the end of the text and
+// the start of it coming round again
+func (c *Marquee) SetGap(n int) { c.gap, c.dirty = n, true }
+
+// GetInterval returns how long the text waits before moving along a cell
+func (c *Marquee) GetInterval() time.Duration { return c.interval }
+
+// SetInterval sets how long the text waits before moving along a cell
+func (c *Marquee) SetInterval(d time.Duration) { c.interval = d }
+
+// IsScrolling returns whether the text is too wide for the marquee, so
+// it scrolls
+func (c *Marquee) IsScrolling() bool { return TextWidth(c.text) > c.innerWidth() }
+
+// SetBordered sets whether the marquee has a border. A bordered marquee
+// is at least 2 high, so the border goes around the text rather than
+// over it.
+func (c *Marquee) SetBordered(b bool) {
+	c.BaseControl.SetBordered(b)
+	if b && c.height < 2 {
+		c.height = 2
+	}
+}
+
+// innerWidth is how wide the text can be inside the border
+func (c *Marquee) innerWidth() int {
+	if c.bordered {
+		return c.width - 1
+	}
+	return c.width
+}
+
+// loop returns the text and gap that goes round, as runes
+func (c *Marquee) loop() []rune {
+	ret := []rune(c.text)
+	for k := 0; k < c.gap; k++ {
+		ret = append(ret, ' ')
+	}
+	return ret
+}
+
+// IsAnimating returns whether the text is moving along
+func (c *Marquee) IsAnimating() bool { return c.interval > 0 && c.IsScrolling() }
+
+// Tick moves the text along a cell for every interval that has passed
+func (c *Marquee) Tick(now time.Time) bool {
+	if !c.IsScrolling() || c.interval <= 0 {
+		c.last = time.Time{}
+		return false
+	}
+	if c.last.IsZero() {
+		c.last = now
+		return false
+	}
+	steps := int(now.Sub(c.last) / c.interval)
+	if steps <= 0 {
+		return false
+	}
+	c.offset = (c.offset + steps) % len(c.loop())
+	c.last = c.last.Add(time.Duration(steps) * c.interval)
+	return true
+}
+
+// HandleEvent accepts the termbox event and returns whether it was consumed
+func (c *Marquee) HandleEvent(event termbox.Event) bool {
+	return false
+}
+
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *Marquee) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *Marquee) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
+// Draw outputs the part of the text that's showing on the screen
+func (c *Marquee) Draw() {
+	x, y, w := c.x, c.y, c.innerWidth()
+	if c.bordered {
+		// Layouts can still make it shorter than SetBordered does
+		DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+maxInt(c.height, 2), c.borderStyle, "", c.fg, c.bg)
+		x, y = x+1, y+1
+	}
+	txt := c.text
+	if c.IsScrolling() {
+		lp := c.loop()
+		txt = string(append(lp[c.offset:], lp[:c.offset]...))
+	}
+	DrawStringAtPoint(AlignText(TruncateText(txt, w), w, AlignLeft), x, y, c.fg, c.bg)
+}
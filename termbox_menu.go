@@ -4,38 +4,32 @@ import "github.com/nsf/termbox-go"
 
 // Menu is a menu with a list of options
 type Menu struct {
-	id      string
-	title   string
-	options []MenuOption
-	// If height is -1, then it is adaptive to the menu
-	x, y, width, height    int
+	BaseControl
+	title                  string
+	options                []MenuOption
 	showHelp               bool
 	cursor                 int
-	bg, fg                 termbox.Attribute
 	selectedBg, selectedFg termbox.Attribute
 	disabledBg, disabledFg termbox.Attribute
 	selectedDisabledBg     termbox.Attribute
 	selectedDisabledFg     termbox.Attribute
-	activeFg, activeBg     termbox.Attribute
 	isDone                 bool
-	bordered               bool
-	vimMode                bool
-	tabSkip                bool
-	active                 bool
+	keymap                 *Keymap
 	canSelectDisabled      bool
+
+	onSelect func(*Menu, *MenuOption)
+	onChange func(*Menu, *MenuOption)
 }
 
 // CreateMenu Creates a menu with the specified attributes
+// If height is -1, then it is adaptive to the menu
 func CreateMenu(title string, options []string, x, y, width, height int, fg, bg termbox.Attribute) *Menu {
-	c := Menu{
-		title: title,
-		x:     x, y: y, width: width, height: height,
-		fg: fg, bg: bg, selectedFg: bg, selectedBg: fg,
+	c := Menu{BaseControl: CreateBaseControl(x, y, width, height, fg, bg),
+		title:      title,
+		selectedFg: bg, selectedBg: fg,
 		disabledFg: bg, disabledBg: bg,
-		activeFg: fg, activeBg: bg,
-		bordered: true,
-		tabSkip:  false,
 	}
+	c.bordered = true
 	for _, line := range options {
 		c.options = append(c.options, MenuOption{text: line})
 	}
@@ -45,29 +39,13 @@ func CreateMenu(title string, options []string, x, y, width, height int, fg, bg
 	return &c
 }
 
-func (c *Menu) SetActiveFgColor(fg termbox.Attribute) { c.activeFg = fg }
-
-func (c *Menu) SetActiveBgColor(bg termbox.Attribute) { c.activeBg = bg }
-
-func (c *Menu) SetActive(a bool) { c.active = a }
-
-func (c *Menu) IsActive() bool { return c.active }
-
-// GetID returns this control's ID
-func (c *Menu) GetID() string { return c.id }
-
-// SetID sets this control's ID
-func (c *Menu) SetID(newID string) { c.id = newID }
-
-func (c *Menu) IsTabSkipped() bool { return c.tabSkip }
-func (c *Menu) SetTabSkip(b bool)  { c.tabSkip = b }
-
 // GetTitle returns the current title of the menu
 func (c *Menu) GetTitle() string { return c.title }
 
 // SetTitle sets the current title of the menu to s
 func (c *Menu) SetTitle(s string) {
 	c.title = s
+	c.dirty = true
 }
 
 // GetOptions returns the current options of the menu
@@ -78,6 +56,7 @@ func (c *Menu) GetOptions() []MenuOption {
 // SetOptions set the menu's options to opts
 func (c *Menu) SetOptions(opts []MenuOption) {
 	c.options = opts
+	c.dirty = true
 }
 
 // SetOptionsFromStrings sets the options of this menu from a slice of strings
@@ -90,38 +69,6 @@ func (c *Menu) SetOptionsFromStrings(opts []string) {
 	c.SetSelectedOption(c.GetOptionFromIndex(0))
 }
 
-// GetX returns the current x coordinate of the menu
-func (c *Menu) GetX() int { return c.x }
-
-// SetX sets the current x coordinate of the menu to x
-func (c *Menu) SetX(x int) {
-	c.x = x
-}
-
-// GetY returns the current y coordinate of the menu
-func (c *Menu) GetY() int { return c.y }
-
-// SetY sets the current y coordinate of the menu to y
-func (c *Menu) SetY(y int) {
-	c.y = y
-}
-
-// GetWidth returns the current width of the menu
-func (c *Menu) GetWidth() int { return c.width }
-
-// SetWidth sets the current menu width to width
-func (c *Menu) SetWidth(width int) {
-	c.width = width
-}
-
-// GetHeight returns the current height of the menu
-func (c *Menu) GetHeight() int { return c.height }
-
-// SetHeight set the height of the menu to height
-func (c *Menu) SetHeight(height int) {
-	c.height = height
-}
-
 // GetSelectedOption returns the current selected option
 func (c *Menu) GetSelectedOption() *MenuOption {
 	idx := c.GetSelectedIndex()
@@ -171,6 +118,7 @@ func (c *Menu) SetSelectedIndex(idx int) {
 		}
 		c.SetSelectedOption(&c.options[idx])
 	}
+	c.dirty = true
 }
 
 // SetSelectedOption sets the current selected option to v (if it's valid)
@@ -182,10 +130,12 @@ func (c *Menu) SetSelectedOption(v *MenuOption) {
 			c.options[idx].Unselect()
 		}
 	}
+	c.dirty = true
 }
 
 // SelectPrevOption Decrements the selected option (if it can)
 func (c *Menu) SelectPrevOption() {
+	c.dirty = true
 	idx := c.GetSelectedIndex()
 	for idx >= 0 {
 		idx--
@@ -201,6 +151,7 @@ func (c *Menu) SelectPrevOption() {
 
 // SelectNextOption Increments the selected option (if it can)
 func (c *Menu) SelectNextOption() {
+	c.dirty = true
 	idx := c.GetSelectedIndex()
 	for idx < len(c.options) {
 		idx++
@@ -216,6 +167,7 @@ func (c *Menu) SelectNextOption() {
 
 // SelectPageUpOption Goes up 'menu height' options
 func (c *Menu) SelectPageUpOption() {
+	c.dirty = true
 	idx := c.GetSelectedIndex()
 	idx -= c.height
 	if idx < 0 {
@@ -227,6 +179,7 @@ func (c *Menu) SelectPageUpOption() {
 
 // SelectPageDownOption Goes down 'menu height' options
 func (c *Menu) SelectPageDownOption() {
+	c.dirty = true
 	idx := c.GetSelectedIndex()
 	idx += c.height
 	if idx >= len(c.options) {
@@ -238,12 +191,14 @@ func (c *Menu) SelectPageDownOption() {
 
 // SelectFirstOption Goes to the top
 func (c *Menu) SelectFirstOption() {
+	c.dirty = true
 	c.SetSelectedIndex(0)
 	return
 }
 
 // SelectLastOption Goes to the bottom
 func (c *Menu) SelectLastOption() {
+	c.dirty = true
 	c.SetSelectedIndex(len(c.options) - 1)
 	return
 }
@@ -253,6 +208,7 @@ func (c *Menu) SetOptionDisabled(idx int) {
 	if len(c.options) > idx {
 		c.GetOptionFromIndex(idx).Disable()
 	}
+	c.dirty = true
 }
 
 // SetOptionEnabled Enables the specified option
@@ -260,6 +216,7 @@ func (c *Menu) SetOptionEnabled(idx int) {
 	if len(c.options) > idx {
 		c.GetOptionFromIndex(idx).Enable()
 	}
+	c.dirty = true
 }
 
 // HelpIsShown returns true or false if the help is displayed
@@ -268,94 +225,181 @@ func (c *Menu) HelpIsShown() bool { return c.showHelp }
 // ShowHelp sets whether or not to display the help text
 func (c *Menu) ShowHelp(b bool) {
 	c.showHelp = b
+	c.dirty = true
 }
 
-func (c *Menu) GetFgColor() termbox.Attribute   { return c.fg }
-func (c *Menu) SetFgColor(fg termbox.Attribute) { c.fg = fg }
-func (c *Menu) GetBgColor() termbox.Attribute   { return c.bg }
-func (c *Menu) SetBgColor(bg termbox.Attribute) { c.bg = bg }
-
 func (c *Menu) GetSelectedFgColor() termbox.Attribute   { return c.selectedFg }
-func (c *Menu) SetSelectedFgColor(fg termbox.Attribute) { c.selectedFg = fg }
+func (c *Menu) SetSelectedFgColor(fg termbox.Attribute) { c.selectedFg, c.dirty = fg, true }
 func (c *Menu) GetSelectedBgColor() termbox.Attribute   { return c.selectedBg }
-func (c *Menu) SetSelectedBgColor(bg termbox.Attribute) { c.selectedBg = bg }
+func (c *Menu) SetSelectedBgColor(bg termbox.Attribute) { c.selectedBg, c.dirty = bg, true }
 
-func (c *Menu) GetSelectedDisabledFgColor() termbox.Attribute   { return c.selectedDisabledFg }
-func (c *Menu) SetSelectedDisabledFgColor(fg termbox.Attribute) { c.selectedDisabledFg = fg }
-func (c *Menu) GetSelectedDisabledBgColor() termbox.Attribute   { return c.selectedDisabledBg }
-func (c *Menu) SetSelectedDisabledBgColor(bg termbox.Attribute) { c.selectedDisabledBg = bg }
+func (c *Menu) GetSelectedDisabledFgColor() termbox.Attribute { return c.selectedDisabledFg }
+func (c *Menu) SetSelectedDisabledFgColor(fg termbox.Attribute) {
+	c.selectedDisabledFg, c.dirty = fg, true
+}
+func (c *Menu) GetSelectedDisabledBgColor() termbox.Attribute { return c.selectedDisabledBg }
+func (c *Menu) SetSelectedDisabledBgColor(bg termbox.Attribute) {
+	c.selectedDisabledBg, c.dirty = bg, true
+}
 
 func (c *Menu) GetDisabledFgColor() termbox.Attribute   { return c.disabledFg }
-func (c *Menu) SetDisabledFgColor(fg termbox.Attribute) { c.disabledFg = fg }
+func (c *Menu) SetDisabledFgColor(fg termbox.Attribute) { c.disabledFg, c.dirty = fg, true }
 func (c *Menu) GetDisabledBgColor() termbox.Attribute   { return c.disabledBg }
-func (c *Menu) SetDisabledBgColor(bg termbox.Attribute) { c.disabledBg = bg }
+func (c *Menu) SetDisabledBgColor(bg termbox.Attribute) { c.disabledBg, c.dirty = bg, true }
 
 // IsDone returns whether the user has answered the modal
 func (c *Menu) IsDone() bool { return c.isDone }
 
-// SetDone sets whether the modal has completed it's purpose
-func (c *Menu) SetDone(b bool) {
-	c.isDone = b
+// SetOnSelect sets a function that is called with the option the user chooses
+func (c *Menu) SetOnSelect(f func(m *Menu, o *MenuOption)) { c.onSelect = f }
+
+// SetOnChange sets a function that is called with the newly selected
+// option whenever the user moves the selection
+func (c *Menu) SetOnChange(f func(m *Menu, o *MenuOption)) { c.onChange = f }
+
+// choose finishes the menu with the selected option
+func (c *Menu) choose() {
+	c.isDone = true
+	if c.onSelect != nil {
+		c.onSelect(c, c.GetSelectedOption())
+	}
 }
 
-// IsBordered returns true or false if this menu has a border
-func (c *Menu) IsBordered() bool { return c.bordered }
+// selectionMoved lets the OnChange function know if the
+// selection isn't at prevIdx anymore, and returns whether it moved
+func (c *Menu) selectionMoved(prevIdx int) bool {
+	if c.GetSelectedIndex() == prevIdx {
+		return false
+	}
+	if c.onChange != nil {
+		c.onChange(c, c.GetSelectedOption())
+	}
+	return true
+}
 
-// SetBordered sets whether we render a border around the menu
-func (c *Menu) SetBordered(b bool) {
-	c.bordered = b
+// SetDone sets whether the modal has completed it's purpose
+func (c *Menu) SetDone(b bool) {
+	c.isDone = b
+	c.dirty = true
 }
 
-// EnableVimMode Enables h,j,k,l navigation
+// EnableVimMode Enables h,j,k,l navigation by using the VimKeymap
 func (c *Menu) EnableVimMode() {
-	c.vimMode = true
+	c.keymap = VimKeymap
 }
 
-// DisableVimMode Disables h,j,k,l navigation
+// DisableVimMode Disables h,j,k,l navigation by going back to the DefaultKeymap
 func (c *Menu) DisableVimMode() {
-	c.vimMode = false
+	c.keymap = nil
+}
+
+// GetKeymap returns the keymap the menu uses
+func (c *Menu) GetKeymap() *Keymap { return ownKeymap(&c.keymap) }
+
+// SetKeymap sets the keymap the menu uses, nil uses the DefaultKeymap
+func (c *Menu) SetKeymap(k *Keymap) {
+	c.keymap = k
 }
 
 func (c *Menu) SetCanSelectDisabled(b bool) {
 	c.canSelectDisabled = b
 }
 
+// ApplyTheme sets the colors of the control from the theme t
+func (c *Menu) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
+	c.borderStyle = t.BorderStyle
+	c.selectedFg, c.selectedBg = t.Selected.Fg, t.Selected.Bg
+	c.disabledFg, c.disabledBg = t.Disabled.Fg, t.Disabled.Bg
+	c.selectedDisabledFg, c.selectedDisabledBg = t.Disabled.Fg, t.Selected.Bg
+}
+
 // HandleEvent handles the termbox event and returns whether it was consumed
 func (c *Menu) HandleEvent(event termbox.Event) bool {
-	if event.Key == termbox.KeyEnter || event.Key == termbox.KeySpace {
-		c.isDone = true
-		return true
-	}
 	currentIdx := c.GetSelectedIndex()
-	switch event.Key {
-	case termbox.KeyArrowUp:
+	switch c.keymap.Match(event, ActionAccept, ActionActivate, ActionSelectPrev, ActionSelectNext,
+		ActionPageUp, ActionPageDown, ActionSelectFirst, ActionSelectLast) {
+	case ActionAccept, ActionActivate:
+		c.choose()
+		return true
+	case ActionSelectPrev:
 		c.SelectPrevOption()
-	case termbox.KeyArrowDown:
+	case ActionSelectNext:
 		c.SelectNextOption()
-	case termbox.KeyArrowLeft:
+	case ActionPageUp:
 		c.SelectPageUpOption()
-	case termbox.KeyArrowRight:
+	case ActionPageDown:
 		c.SelectPageDownOption()
+	case ActionSelectFirst:
+		c.SelectFirstOption()
+	case ActionSelectLast:
+		c.SelectLastOption()
 	}
-	if c.vimMode {
-		switch event.Ch {
-		case 'j':
-			c.SelectNextOption()
-		case 'k':
-			c.SelectPrevOption()
+	return c.selectionMoved(currentIdx)
+}
+
+// HandleMouse selects the option that was clicked, accepting it on a double
+// click, and moves the selection with the wheel
+func (c *Menu) HandleMouse(ev MouseEvent) bool {
+	currentIdx := c.GetSelectedIndex()
+	switch ev.Action {
+	case MouseWheelUp:
+		c.SelectPrevOption()
+		c.selectionMoved(currentIdx)
+		return true
+	case MouseWheelDown:
+		c.SelectNextOption()
+		c.selectionMoved(currentIdx)
+		return true
+	case MouseClick, MouseDoubleClick:
+		top := 0
+		if c.bordered {
+			top = 1
 		}
-		if event.Key == termbox.KeyCtrlF {
-			c.SelectPageDownOption()
-		} else if event.Key == termbox.KeyCtrlB {
-			c.SelectPageUpOption()
+		firstDispIdx, lastDispIdx := c.visibleOptions()
+		idx := firstDispIdx + ev.Y - top
+		if ev.Y < top || idx > lastDispIdx || idx >= len(c.options) {
+			return false
+		}
+		if c.options[idx].IsDisabled() && !c.canSelectDisabled {
+			return false
+		}
+		c.SetSelectedIndex(idx)
+		c.selectionMoved(currentIdx)
+		if ev.Action == MouseDoubleClick {
+			c.choose()
 		}
-	}
-	if c.GetSelectedIndex() != currentIdx {
 		return true
 	}
 	return false
 }
 
+// visibleOptions returns the indexes of the first and last options
+// that fit in the menu, keeping the selected option in view
+func (c *Menu) visibleOptions() (int, int) {
+	firstDispIdx := 0
+	lastDispIdx := len(c.options) - 1
+	if len(c.options) > c.height-2 {
+		lastDispIdx = c.height - 2
+	}
+	if c.GetSelectedIndex() > c.height-2 {
+		firstDispIdx = c.GetSelectedIndex() - (c.height - 2)
+		lastDispIdx = c.GetSelectedIndex()
+	}
+	return firstDispIdx, lastDispIdx
+}
+
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *Menu) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *Menu) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
 // Draw draws the modal
 func (c *Menu) Draw() {
 	useFg, useBg := c.fg, c.bg
@@ -368,7 +412,6 @@ func (c *Menu) Draw() {
 	optionStartX := c.x
 	optionStartY := c.y
 	optionWidth := c.width
-	_ = optionWidth
 	optionHeight := c.height
 	if optionHeight == -1 {
 		optionHeight = len(c.options)
@@ -377,15 +420,15 @@ func (c *Menu) Draw() {
 		pct := float64(c.GetSelectedIndex()) / float64(len(c.options))
 		if c.title == "" {
 			if len(c.options) > c.height-2 {
-				DrawBorderWithPct(c.x, c.y, c.x+c.width, c.y+c.height, pct, useFg, useBg)
+				DrawStyledBorderWithPct(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", pct, useFg, useBg)
 			} else {
-				DrawBorder(c.x, c.y, c.x+c.width, c.y+c.height, useFg, useBg)
+				DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", useFg, useBg)
 			}
 		} else {
 			if len(c.options) > c.height-2 {
-				DrawBorderWithTitleAndPct(c.x, c.y, c.x+c.width, c.y+c.height, " "+c.title+" ", pct, useFg, useBg)
+				DrawStyledBorderWithPct(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, " "+c.title+" ", pct, useFg, useBg)
 			} else {
-				DrawBorderWithTitle(c.x, c.y, c.x+c.width, c.y+c.height, " "+c.title+" ", useFg, useBg)
+				DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, " "+c.title+" ", useFg, useBg)
 			}
 		}
 		optionStartX = c.x + 1
@@ -395,18 +438,10 @@ func (c *Menu) Draw() {
 	}
 
 	if len(c.options) > 0 {
-		firstDispIdx := 0
-		lastDispIdx := len(c.options) - 1
-		if len(c.options) > c.height-2 {
-			lastDispIdx = c.height - 2
-		}
-		if c.GetSelectedIndex() > c.height-2 {
-			firstDispIdx = c.GetSelectedIndex() - (c.height - 2)
-			lastDispIdx = c.GetSelectedIndex()
-		}
+		firstDispIdx, lastDispIdx := c.visibleOptions()
 		for idx := firstDispIdx; idx < lastDispIdx+1; idx++ {
 			currOpt := &c.options[idx]
-			outTxt := currOpt.GetText()
+			outTxt := TruncateText(currOpt.GetText(), optionWidth)
 			if currOpt.IsDisabled() {
 				if c.GetSelectedOption() == currOpt {
 					DrawStringAtPoint(outTxt, optionStartX, optionStartY, c.selectedDisabledFg, c.selectedDisabledBg)
@@ -0,0 +1,297 @@
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+// doneControl is a control, like the modals, that is finished with at some point
+type doneControl interface {
+	IsDone() bool
+	SetDone(bool)
+}
+
+// showControl is a control that can be hidden and shown again
+type showControl interface {
+	Show()
+}
+
+// modalLayer is a control on a ModalStack and where it goes
+type modalLayer struct {
+	control   Control
+	placement *Placement
+}
+
+// ModalStack draws modals on top of a base control (usually the screen's
+// Frame). The modal on top gets all of the input until it's done, then it's
+// popped off and whatever was under it gets the input back, with the same
+// control focused as before. Everything under the top modal is shaded.
+type ModalStack struct {
+	BaseControl
+	base   Control
+	layers []modalLayer
+	shaded bool
+
+	onPop func(*ModalStack, Control)
+}
+
+// CreateModalStack creates a modal stack over base, at x, y that is w by h.
+// Modals are placed within that area. The stack's colors are the colors
+// everything under the top modal is shaded with.
+func CreateModalStack(base Control, x, y, w, h int) *ModalStack {
+	c := ModalStack{BaseControl: CreateBaseControl(x, y, w, h, termbox.ColorBlack|termbox.AttrBold, termbox.ColorBlack),
+		base: base, shaded: true,
+	}
+	return &c
+}
+
+// GetBase returns the control under all of the modals
+func (c *ModalStack) GetBase() Control { return c.base }
+
+// SetBase sets the control under all of the modals
+func (c *ModalStack) SetBase(t Control) {
+	c.base = t
+	c.dirty = true
+	c.refresh()
+}
+
+// Push puts the modal t on top of the stack where it is now, it gets
+// all input until it's done
+func (c *ModalStack) Push(t Control) { c.push(t, nil) }
+
+// PushPlaced puts the modal t on top of the stack placed by p, it gets
+// all input until it's done
+func (c *ModalStack) PushPlaced(t Control, p Placement) { c.push(t, &p) }
+
+func (c *ModalStack) push(t Control, p *Placement) {
+	old := c.getFocused()
+	if top := c.getTop(); top != nil {
+		top.SetActive(false)
+	}
+	if v, ok := t.(doneControl); ok {
+		v.SetDone(false)
+	}
+	if v, ok := t.(showControl); ok {
+		v.Show()
+	}
+	c.layers = append(c.layers, modalLayer{control: t, placement: p})
+	c.dirty = true
+	c.place()
+	c.focusChanged(old)
+}
+
+// Pop takes the modal on top of the stack off and returns it, or nil
+// if there aren't any modals
+func (c *ModalStack) Pop() Control {
+	if len(c.layers) == 0 {
+		return nil
+	}
+	old := c.getFocused()
+	t := c.layers[len(c.layers)-1].control
+	c.layers = c.layers[:len(c.layers)-1]
+	c.dirty = true
+	t.SetActive(false)
+	c.focusChanged(old)
+	if c.onPop != nil {
+		c.onPop(c, t)
+	}
+	return t
+}
+
+// SetOnPop sets a function that is called with each modal as it's popped
+func (c *ModalStack) SetOnPop(f func(s *ModalStack, t Control)) { c.onPop = f }
+
+// GetTop returns the modal on top of the stack, or nil
+func (c *ModalStack) GetTop() Control {
+	if len(c.layers) == 0 {
+		return nil
+	}
+	return c.layers[len(c.layers)-1].control
+}
+
+// GetDepth returns how many modals are on the stack
+func (c *ModalStack) GetDepth() int { return len(c.layers) }
+
+// getTop returns whatever gets input, the top modal or the base
+func (c *ModalStack) getTop() Control {
+	if t := c.GetTop(); t != nil {
+		return t
+	}
+	return c.base
+}
+
+// getFocused returns the focused control in whatever gets input
+func (c *ModalStack) getFocused() Control {
+	return CreateFocusManager(c.getTop()).GetFocused()
+}
+
+// refresh makes whatever gets input active
+func (c *ModalStack) refresh() {
+	if t := c.getTop(); t != nil {
+		t.SetActive(c.active)
+	}
+}
+
+// focusChanged refreshes the active controls and, if the focus
+// has moved away from old, lets the controls know
+func (c *ModalStack) focusChanged(old Control) {
+	c.refresh()
+	curr := c.getFocused()
+	if curr == old {
+		return
+	}
+	if v, ok := old.(focusListener); ok {
+		v.OnBlur()
+	}
+	if v, ok := curr.(focusListener); ok {
+		v.OnFocus()
+	}
+}
+
+// popDone pops every modal off the top that is done
+func (c *ModalStack) popDone() {
+	for len(c.layers) > 0 {
+		v, ok := c.GetTop().(doneControl)
+		if !ok || !v.IsDone() {
+			return
+		}
+		c.Pop()
+	}
+}
+
+// place moves and sizes the placed modals to fit the stack
+func (c *ModalStack) place() {
+	for _, l := range c.layers {
+		if l.placement != nil {
+			l.placement.Apply(l.control, c.x, c.y, c.width, c.height)
+		}
+	}
+}
+
+// IsShaded returns whether everything under the top modal is shaded
+func (c *ModalStack) IsShaded() bool { return c.shaded }
+
+// SetShaded sets whether everything under the top modal is shaded
+func (c *ModalStack) SetShaded(b bool) { c.shaded, c.dirty = b, true }
+
+// SetShadeColors sets the colors everything under the top modal is shaded with
+func (c *ModalStack) SetShadeColors(fg, bg termbox.Attribute) {
+	c.fg, c.bg = fg, bg
+	c.dirty = true
+}
+
+// IsBordered returns false, the stack doesn't have a border
+func (c *ModalStack) IsBordered() bool { return false }
+
+// SetBordered does nothing, the stack doesn't have a border
+func (c *ModalStack) SetBordered(b bool) {}
+
+// SetActive sets whether the stack is active, only the top modal
+// (or the base if there aren't any) is active along with it
+func (c *ModalStack) SetActive(a bool) {
+	c.BaseControl.SetActive(a)
+	c.refresh()
+}
+
+// ApplyTheme sets the colors of the base and every modal from the theme t
+func (c *ModalStack) ApplyTheme(t *Theme) {
+	if c.base != nil {
+		ApplyTheme(c.base, t)
+	}
+	for _, l := range c.layers {
+		ApplyTheme(l.control, t)
+	}
+}
+
+// damageControls returns the base and every modal, so damage
+// is tracked for them and they're ticked
+func (c *ModalStack) damageControls() []Control {
+	var ret []Control
+	if c.base != nil {
+		ret = append(ret, c.base)
+	}
+	for _, l := range c.layers {
+		ret = append(ret, l.control)
+	}
+	return ret
+}
+
+// HandleEvent sends the event to the top modal, or the base if there
+// aren't any modals. Modals that are done are popped off afterwards.
+// While there is a modal on the stack every event is consumed.
+func (c *ModalStack) HandleEvent(event termbox.Event) bool {
+	top := c.getTop()
+	if top == nil {
+		return false
+	}
+	c.place()
+	ret := CreateFocusManager(top).HandleEvent(event)
+	if top == c.base {
+		return ret
+	}
+	c.popDone()
+	return true
+}
+
+// HandleMouse sends the mouse event to the top modal, or the base if
+// there aren't any modals. Clicks outside of the top modal are dropped.
+func (c *ModalStack) HandleMouse(ev MouseEvent) bool {
+	top := c.getTop()
+	if top == nil {
+		return false
+	}
+	c.place()
+	fm := CreateFocusManager(top)
+	old := fm.GetFocused()
+	var ret bool
+	ev = ev.Offset(top.GetX()-c.x, top.GetY()-c.y)
+	if top == c.base || controlContains(top, ev.X+top.GetX(), ev.Y+top.GetY()) ||
+		ev.Action == MouseDrag || ev.Action == MouseRelease {
+		ret = SendMouseEvent(top, ev)
+	}
+	c.focusChanged(old)
+	if top == c.base {
+		return ret
+	}
+	c.popDone()
+	return true
+}
+
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *ModalStack) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *ModalStack) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
+// Draw draws the base, then each modal on top of it, shading
+// everything under the top modal
+func (c *ModalStack) Draw() {
+	c.place()
+	// Drawn through DrawClipped (clipped to the whole canvas) so
+	// damage is tracked for each of them
+	w, h := GetCanvas().Size()
+	if c.base != nil {
+		DrawClipped(c.base, 0, 0, w, h)
+	}
+	for idx, l := range c.layers {
+		if c.shaded && idx == len(c.layers)-1 {
+			c.shade()
+		}
+		DrawClipped(l.control, 0, 0, w, h)
+	}
+}
+
+// shade recolors everything already drawn in the stack's area
+func (c *ModalStack) shade() {
+	cnv := GetCanvas()
+	for y := c.y; y < c.y+c.height; y++ {
+		for x := c.x; x < c.x+c.width; x++ {
+			ch := cnv.GetCell(x, y).Ch
+			if ch == 0 {
+				ch = ' '
+			}
+			cnv.SetCell(x, y, ch, c.fg, c.bg)
+		}
+	}
+}
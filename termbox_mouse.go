@@ -0,0 +1,165 @@
+package termboxUtil
+
+import (
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// MouseAction is what the mouse did
+type MouseAction int
+
+const (
+	// MouseClick is a button being pressed
+	MouseClick MouseAction = iota
+	// MouseDoubleClick is a button being pressed twice in the same spot
+	// within DoubleClickTime
+	MouseDoubleClick
+	// MouseDrag is the mouse moving with a button held down
+	MouseDrag
+	// MouseRelease is a button being let go
+	MouseRelease
+	// MouseWheelUp is the wheel being scrolled up
+	MouseWheelUp
+	// MouseWheelDown is the wheel being scrolled down
+	MouseWheelDown
+)
+
+// DoubleClickTime is how close together two clicks have to be
+// to count as a double click
+var DoubleClickTime = 400 * time.Millisecond
+
+// MouseEvent is a mouse event with X and Y relative to
+// the top left corner of the control receiving it
+type MouseEvent struct {
+	Action MouseAction
+	// Button is termbox.MouseLeft, MouseMiddle or MouseRight
+	// (it's 0 for releases and the wheel)
+	Button termbox.Key
+	X, Y   int
+	Mod    termbox.Modifier
+}
+
+// Offset returns the event moved into the coordinates of
+// something at x, y
+func (ev MouseEvent) Offset(x, y int) MouseEvent {
+	ev.X, ev.Y = ev.X-x, ev.Y-y
+	return ev
+}
+
+// mouseControl is a control that handles mouse events
+type mouseControl interface {
+	HandleMouse(MouseEvent) bool
+}
+
+// SendMouseEvent sends ev to the control t, if it handles the mouse,
+// and returns whether it was consumed
+func SendMouseEvent(t Control, ev MouseEvent) bool {
+	if v, ok := t.(mouseControl); ok && v.HandleMouse(ev) {
+		markLeafDirty(t)
+		return true
+	}
+	return false
+}
+
+// mouseTracker remembers the last press to spot double clicks
+type mouseTracker struct {
+	lastButton termbox.Key
+	lastX      int
+	lastY      int
+	lastTime   time.Time
+	wasDouble  bool
+}
+
+var mouseState mouseTracker
+
+// ToMouseEvent converts a termbox mouse event into a MouseEvent in
+// screen coordinates. Termbox mouse events should be converted exactly
+// once, as they come in, so double clicks are spotted correctly.
+func ToMouseEvent(event termbox.Event) MouseEvent {
+	return mouseState.convert(event, time.Now())
+}
+
+func (m *mouseTracker) convert(event termbox.Event, now time.Time) MouseEvent {
+	ev := MouseEvent{X: event.MouseX, Y: event.MouseY, Mod: event.Mod &^ termbox.ModMotion}
+	switch event.Key {
+	case termbox.MouseWheelUp:
+		ev.Action = MouseWheelUp
+	case termbox.MouseWheelDown:
+		ev.Action = MouseWheelDown
+	case termbox.MouseRelease:
+		ev.Action = MouseRelease
+	default:
+		ev.Button = event.Key
+		if event.Mod&termbox.ModMotion != 0 {
+			ev.Action = MouseDrag
+			return ev
+		}
+		ev.Action = MouseClick
+		if !m.wasDouble && event.Key == m.lastButton && ev.X == m.lastX && ev.Y == m.lastY &&
+			now.Sub(m.lastTime) <= DoubleClickTime {
+			ev.Action = MouseDoubleClick
+		}
+		m.wasDouble = ev.Action == MouseDoubleClick
+		m.lastButton, m.lastX, m.lastY, m.lastTime = event.Key, ev.X, ev.Y, now
+	}
+	return ev
+}
+
+// controlContains returns whether x, y is inside of the control t,
+// including the border it draws at x+width and y+height
+func controlContains(t Control, x, y int) bool {
+	w, h := t.GetWidth()+borderCells(t), t.GetHeight()+borderCells(t)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return inRect(x, y, t.GetX(), t.GetY(), w, h)
+}
+
+// inRect returns whether x, y is inside of the w by h rectangle at rx, ry
+func inRect(x, y, rx, ry, w, h int) bool {
+	return x >= rx && x < rx+w && y >= ry && y < ry+h
+}
+
+// mouseRouter sends mouse events on to the controls of a container,
+// keeping track of which control a drag started in
+type mouseRouter struct {
+	capture Control
+	offX    int
+	offY    int
+}
+
+// route sends ev (in the container's coordinates) to whichever of controls
+// it hits, or the control that the button was pressed in for drags and
+// releases. Controls are at their position minus offX, offY. It returns
+// the index of the control that was clicked (or -1) and whether the event
+// was consumed.
+func (r *mouseRouter) route(controls []Control, ev MouseEvent, offX, offY int) (int, bool) {
+	if ev.Action == MouseDrag || ev.Action == MouseRelease {
+		t := r.capture
+		if ev.Action == MouseRelease {
+			r.capture = nil
+		}
+		if t == nil {
+			return -1, false
+		}
+		return -1, SendMouseEvent(t, ev.Offset(t.GetX()-r.offX, t.GetY()-r.offY))
+	}
+	// Controls drawn last are on top, so check them first
+	for idx := len(controls) - 1; idx >= 0; idx-- {
+		t := controls[idx]
+		if !controlContains(t, ev.X+offX, ev.Y+offY) {
+			continue
+		}
+		if ev.Action == MouseClick || ev.Action == MouseDoubleClick {
+			r.capture, r.offX, r.offY = t, offX, offY
+			SendMouseEvent(t, ev.Offset(t.GetX()-offX, t.GetY()-offY))
+			return idx, true
+		}
+		return -1, SendMouseEvent(t, ev.Offset(t.GetX()-offX, t.GetY()-offY))
+	}
+	return -1, false
+}
@@ -0,0 +1,74 @@
+package termboxUtil_test
+
+import (
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+// click sends a left click at x, y on the screen to the container t
+func click(t termboxUtil.Control, x, y int) bool {
+	ev := termboxUtil.ToMouseEvent(screentest.Click(x, y)).Offset(t.GetX(), t.GetY())
+	return termboxUtil.SendMouseEvent(t, ev)
+}
+
+func TestClickMovesActiveControl(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	for _, tc := range []struct {
+		name string
+		ctr  interface {
+			termboxUtil.Control
+			AddControl(termboxUtil.Control)
+		}
+	}{
+		{"frame", termboxUtil.CreateFrame(0, 0, 20, 4, fg, bg)},
+		{"scrollframe", termboxUtil.CreateScrollFrame(0, 0, 20, 4, fg, bg)},
+	} {
+		one := termboxUtil.CreateInputField(0, 0, 10, 1, fg, bg)
+		two := termboxUtil.CreateInputField(0, 2, 10, 1, fg, bg)
+		tc.ctr.AddControl(one)
+		tc.ctr.AddControl(two)
+		tc.ctr.SetActive(true)
+		if !one.IsActive() || two.IsActive() {
+			t.Fatalf("%s: expected the first field to start out active", tc.name)
+		}
+		if !click(tc.ctr, 3, 2) {
+			t.Errorf("%s: expected the click to be consumed", tc.name)
+		}
+		if one.IsActive() || !two.IsActive() {
+			t.Errorf("%s: expected only the clicked field to be active, got %v and %v", tc.name, one.IsActive(), two.IsActive())
+		}
+	}
+}
+
+func TestClickMovesActiveControlInBox(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	box := termboxUtil.CreateVBox(0, 0, 20, 4, fg, bg)
+	one := termboxUtil.CreateInputField(0, 0, 10, 1, fg, bg)
+	two := termboxUtil.CreateInputField(0, 0, 10, 1, fg, bg)
+	box.AddControl(one, termboxUtil.Fixed(1))
+	box.AddControl(two, termboxUtil.Fixed(1))
+	box.SetActive(true)
+	click(box, 3, 1)
+	if one.IsActive() || !two.IsActive() {
+		t.Errorf("expected only the clicked field to be active, got %v and %v", one.IsActive(), two.IsActive())
+	}
+}
+
+func TestClickOnBorderHitsControl(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	frm := termboxUtil.CreateFrame(0, 0, 20, 8, fg, bg)
+	one := termboxUtil.CreateInputField(1, 1, 8, 2, fg, bg)
+	two := termboxUtil.CreateInputField(1, 5, 8, 1, fg, bg)
+	one.SetBordered(true)
+	frm.AddControl(two)
+	frm.AddControl(one)
+	frm.SetActive(true)
+	// The bottom right corner of the first field's border is at 9, 3
+	click(frm, 9, 3)
+	if !one.IsActive() || two.IsActive() {
+		t.Errorf("expected a click on the border to make the field active, got %v and %v", one.IsActive(), two.IsActive())
+	}
+}
@@ -0,0 +1,107 @@
+package termboxUtil
+
+// Anchor is the edge, corner or center of its parent a control is placed against
+type Anchor int
+
+// The places a control can be anchored
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// Placement is where a control goes in its parent, worked out from the
+// parent's size every time it's drawn rather than fixed when the control
+// is created. Width and Height use the same Sizes as the layout containers,
+// a Percent is of the parent, Flex fills the parent and Auto keeps the
+// control's own size. Sizes are of the space the control takes up, so
+// a bordered control (which draws its border at x+width and y+height)
+// is made a cell smaller each way to fit its border in. A modal can be
+// put in the middle of a frame at 60% of its width with
+//
+//	frame.AddPlacedControl(modal, Centered(Percent(60), Auto()))
+//
+// or on top of everything else with ModalStack.PushPlaced.
+type Placement struct {
+	Anchor Anchor
+	// OffsetX and OffsetY move the control in from the edge it's
+	// anchored to, or from the center
+	OffsetX, OffsetY int
+	Width, Height    Size
+}
+
+// PlaceAt returns a placement anchored at a that is w by h
+func PlaceAt(a Anchor, w, h Size) Placement {
+	return Placement{Anchor: a, Width: w, Height: h}
+}
+
+// Centered returns a placement in the middle of the parent that is w by h
+func Centered(w, h Size) Placement { return PlaceAt(AnchorCenter, w, h) }
+
+// Fill returns a placement that covers the whole parent
+func Fill() Placement { return PlaceAt(AnchorTopLeft, Flex(1), Flex(1)) }
+
+// WithOffset returns the placement moved x, y from its anchor
+func (p Placement) WithOffset(x, y int) Placement {
+	p.OffsetX, p.OffsetY = x, y
+	return p
+}
+
+// resolveSize works out s in a parent that is length long, where the
+// control is currently curr long
+func resolveSize(s Size, length, curr int) int {
+	switch s.Mode {
+	case SizeFixed:
+		return s.clamp(s.Value)
+	case SizePercent:
+		return s.clamp(length * s.Value / 100)
+	case SizeFlex:
+		return s.clamp(length)
+	}
+	return s.clamp(curr)
+}
+
+// resolveAxis works out where something size long goes in a parent that
+// starts at pos and is length long, where align is 0 for the start,
+// 1 for the middle and 2 for the end
+func resolveAxis(align, pos, length, size, offset int) int {
+	switch align {
+	case 1:
+		return pos + (length-size)/2 + offset
+	case 2:
+		return pos + length - size - offset
+	}
+	return pos + offset
+}
+
+// Resolve returns the x, y, width and height the control t has when
+// placed in a parent at x, y that is w by h
+func (p Placement) Resolve(t Control, x, y, w, h int) (int, int, int, int) {
+	b := borderCells(t)
+	cw := resolveSize(p.Width, w, t.GetWidth()+b)
+	ch := resolveSize(p.Height, h, t.GetHeight()+b)
+	if p.Width.Mode == SizeFlex && p.Anchor%3 != 1 {
+		cw = p.Width.clamp(w - p.OffsetX)
+	}
+	if p.Height.Mode == SizeFlex && p.Anchor/3 != 1 {
+		ch = p.Height.clamp(h - p.OffsetY)
+	}
+	return resolveAxis(int(p.Anchor%3), x, w, cw, p.OffsetX),
+		resolveAxis(int(p.Anchor/3), y, h, ch, p.OffsetY), maxInt(cw-b, 0), maxInt(ch-b, 0)
+}
+
+// Apply moves and sizes the control t to where it's placed
+// in a parent at x, y that is w by h
+func (p Placement) Apply(t Control, x, y, w, h int) {
+	cx, cy, cw, ch := p.Resolve(t, x, y, w, h)
+	t.SetX(cx)
+	t.SetY(cy)
+	t.SetWidth(cw)
+	t.SetHeight(ch)
+}
@@ -0,0 +1,40 @@
+package termboxUtil_test
+
+import (
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func TestFillKeepsBorderOnScreen(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	frm := termboxUtil.CreateFrame(5, 5, 1, 1, fg, bg)
+	p := termboxUtil.Fill()
+	x, y, w, h := p.Resolve(frm, 0, 0, 20, 6)
+	if x != 0 || y != 0 || w != 19 || h != 5 {
+		t.Errorf("expected a bordered frame to fill 20x6 at 0, 0 with 19x5, got %dx%d at %d, %d", w, h, x, y)
+	}
+	lbl := termboxUtil.CreateLabel("", 5, 5, 1, 1, fg, bg)
+	if _, _, w, h := p.Resolve(lbl, 0, 0, 20, 6); w != 20 || h != 6 {
+		t.Errorf("expected an unbordered label to fill 20x6, got %dx%d", w, h)
+	}
+}
+
+func TestPushPlacedModal(t *testing.T) {
+	s := screentest.CreateScreen(20, 7)
+	stack := termboxUtil.CreateModalStack(termboxUtil.CreateLabel("", 0, 0, 20, 7, termbox.ColorWhite, termbox.ColorBlack), 0, 0, 20, 7)
+	modal := termboxUtil.CreateAlertModal("Hi", 0, 0, 1, 1, termbox.ColorWhite, termbox.ColorBlack)
+	modal.SetText("")
+	modal.ShowHelp(false)
+	stack.PushPlaced(modal, termboxUtil.Centered(termboxUtil.Fixed(10), termboxUtil.Fixed(5)))
+	s.Draw(stack)
+	// The 10x5 cells the modal takes up, border and all, are in the middle
+	if modal.GetX() != 5 || modal.GetY() != 1 || modal.GetWidth() != 9 || modal.GetHeight() != 4 {
+		t.Errorf("expected the modal to be 9x4 at 5, 1, got %dx%d at %d, %d",
+			modal.GetWidth(), modal.GetHeight(), modal.GetX(), modal.GetY())
+	}
+	s.AssertRune(t, 5, 1, '╔')
+	s.AssertRune(t, 14, 5, '╝')
+}
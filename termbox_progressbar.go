@@ -1,52 +1,44 @@
 package termboxUtil
 
-import "github.com/nsf/termbox-go"
+import (
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// progressBounceInterval is how often an indeterminate bar's block moves
+const progressBounceInterval = 80 * time.Millisecond
 
 // ProgressBar Just contains the data needed to display a progress bar
 type ProgressBar struct {
-	id             string
+	BaseControl
 	total          int
 	progress       int
 	allowOverflow  bool
 	allowUnderflow bool
 	fullChar       rune
 	emptyChar      rune
-	bordered       bool
 	alignment      TextAlignment
 	colorized      bool
 
-	x, y               int
-	width, height      int
-	fg, bg             termbox.Attribute
-	activeFg, activeBg termbox.Attribute
-	active             bool
+	indeterminate bool
+	bouncePos     int
+	bounceDir     int
+	bounceLast    time.Time
 }
 
 // CreateProgressBar Create a progress bar object
 func CreateProgressBar(tot, x, y int, fg, bg termbox.Attribute) *ProgressBar {
-	c := ProgressBar{total: tot,
+	c := ProgressBar{BaseControl: CreateBaseControl(x, y, 10, 1, fg, bg),
+		total:    tot,
 		fullChar: '#', emptyChar: ' ',
-		x: x, y: y, height: 1, width: 10,
-		bordered: true, fg: fg, bg: bg,
-		activeFg: fg, activeBg: bg,
-		alignment: AlignLeft,
+		alignment: AlignLeft, bounceDir: 1,
 	}
+	c.bordered = true
+	c.tabSkip = true
 	return &c
 }
 
-func (c *ProgressBar) SetActiveFgColor(fg termbox.Attribute) { c.activeFg = fg }
-func (c *ProgressBar) SetActiveBgColor(bg termbox.Attribute) { c.activeBg = bg }
-func (c *ProgressBar) SetActive(a bool)                      { c.active = a }
-func (c *ProgressBar) IsActive() bool                        { return c.active }
-
-// GetID returns this control's ID
-func (c *ProgressBar) GetID() string { return c.id }
-
-// SetID sets this control's ID
-func (c *ProgressBar) SetID(newID string) {
-	c.id = newID
-}
-
 // GetProgress returns the curret progress value
 func (c *ProgressBar) GetProgress() int {
 	return c.progress
@@ -57,6 +49,7 @@ func (c *ProgressBar) SetProgress(p int) {
 	if (p <= c.total || c.allowOverflow) || (p >= 0 || c.allowUnderflow) {
 		c.progress = p
 	}
+	c.dirty = true
 }
 
 // IncrProgress increments the current progress of the bar
@@ -64,6 +57,7 @@ func (c *ProgressBar) IncrProgress() {
 	if c.progress < c.total || c.allowOverflow {
 		c.progress++
 	}
+	c.dirty = true
 }
 
 // DecrProgress decrements the current progress of the bar
@@ -71,6 +65,7 @@ func (c *ProgressBar) DecrProgress() {
 	if c.progress > 0 || c.allowUnderflow {
 		c.progress--
 	}
+	c.dirty = true
 }
 
 // GetPercent returns the percent full of the bar
@@ -106,6 +101,7 @@ func (c *ProgressBar) GetFullChar() rune {
 // SetFullChar sets the rune used for 'full'
 func (c *ProgressBar) SetFullChar(f rune) {
 	c.fullChar = f
+	c.dirty = true
 }
 
 // GetEmptyChar gets the rune used for 'empty'
@@ -116,78 +112,94 @@ func (c *ProgressBar) GetEmptyChar() rune {
 // SetEmptyChar sets the rune used for 'empty'
 func (c *ProgressBar) SetEmptyChar(f rune) {
 	c.emptyChar = f
+	c.dirty = true
 }
 
-// GetX Return the x position of the Progress Bar
-func (c *ProgressBar) GetX() int { return c.x }
-
-// SetX set the x position of the ProgressBar to x
-func (c *ProgressBar) SetX(x int) {
-	c.x = x
-}
-
-// GetY Return the y position of the ProgressBar
-func (c *ProgressBar) GetY() int { return c.y }
-
-// SetY Set the y position of the ProgressBar to y
-func (c *ProgressBar) SetY(y int) {
-	c.y = y
-}
-
-// GetHeight returns the height of the progress bar
-// Defaults to 1 (3 if bordered)
-func (c *ProgressBar) GetHeight() int {
-	return c.height
+// Align Tells which direction the progress bar empties
+func (c *ProgressBar) Align(a TextAlignment) {
+	c.alignment = a
+	c.dirty = true
 }
 
-// SetHeight Sets the height of the progress bar
-func (c *ProgressBar) SetHeight(h int) {
-	c.height = h
+// SetColorized sets whether the progress bar should be colored
+// depending on how full it is:
+//
+//	 10% - Red
+//		50% - Yellow
+//		80% - Green
+func (c *ProgressBar) SetColorized(color bool) {
+	c.colorized = color
+	c.dirty = true
 }
 
-// GetWidth returns the width of the progress bar
-func (c *ProgressBar) GetWidth() int {
-	return c.width
+// IsIndeterminate returns whether the bar shows that something is going
+// on without saying how far along it is
+func (c *ProgressBar) IsIndeterminate() bool { return c.indeterminate }
+
+// SetIndeterminate sets whether the bar shows that something is going
+// on without saying how far along it is. An indeterminate bar has a block
+// bouncing back and forth in it, animated by an App's ticks.
+func (c *ProgressBar) SetIndeterminate(b bool) {
+	c.indeterminate = b
+	c.bouncePos, c.bounceDir = 0, 1
+	c.bounceLast = time.Time{}
+	c.dirty = true
 }
 
-// SetWidth Sets the width of the progress bar
-func (c *ProgressBar) SetWidth(w int) {
-	c.width = w
+// bounceWidth returns how wide the block in an indeterminate bar is
+func (c *ProgressBar) bounceWidth() int {
+	if w := (c.width - 2) / 4; w > 1 {
+		return w
+	}
+	return 1
 }
 
-// GetFgColor returns the foreground color
-func (c *ProgressBar) GetFgColor() termbox.Attribute { return c.fg }
+// IsAnimating returns whether the bar is indeterminate, so the block moves
+func (c *ProgressBar) IsAnimating() bool { return c.indeterminate }
 
-// SetFgColor sets the foreground color
-func (c *ProgressBar) SetFgColor(fg termbox.Attribute) {
-	c.fg = fg
+// Tick moves the block in an indeterminate bar along
+func (c *ProgressBar) Tick(now time.Time) bool {
+	if !c.indeterminate {
+		return false
+	}
+	if c.bounceLast.IsZero() {
+		c.bounceLast = now
+		return false
+	}
+	steps := int(now.Sub(c.bounceLast) / progressBounceInterval)
+	if steps <= 0 {
+		return false
+	}
+	c.bounceLast = c.bounceLast.Add(time.Duration(steps) * progressBounceInterval)
+	end := c.width - 2 - c.bounceWidth()
+	for ; steps > 0 && end > 0; steps-- {
+		if c.bouncePos+c.bounceDir < 0 || c.bouncePos+c.bounceDir > end {
+			c.bounceDir = -c.bounceDir
+		}
+		c.bouncePos += c.bounceDir
+	}
+	return true
 }
 
-// GetBgColor returns the background color
-func (c *ProgressBar) GetBgColor() termbox.Attribute { return c.bg }
-
-// SetBgColor sets the current background color
-func (c *ProgressBar) SetBgColor(bg termbox.Attribute) {
-	c.bg = bg
+// ApplyTheme sets the colors of the control from the theme t
+func (c *ProgressBar) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
 }
 
-// Align Tells which direction the progress bar empties
-func (c *ProgressBar) Align(a TextAlignment) {
-	c.alignment = a
+// HandleEvent accepts the termbox event and returns whether it was consumed
+func (c *ProgressBar) HandleEvent(event termbox.Event) bool {
+	return false
 }
 
-// SetColorized sets whether the progress bar should be colored
-// depending on how full it is:
-//  10% - Red
-//	50% - Yellow
-//	80% - Green
-func (c *ProgressBar) SetColorized(color bool) {
-	c.colorized = color
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *ProgressBar) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
 }
 
-// HandleEvent accepts the termbox event and returns whether it was consumed
-func (c *ProgressBar) HandleEvent(event termbox.Event) bool {
-	return false
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *ProgressBar) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
 }
 
 // Draw outputs the input field on the screen
@@ -207,6 +219,16 @@ func (c *ProgressBar) Draw() {
 	drawX, drawY := c.x, c.y
 	fillWidth, fillHeight := c.width-2, c.height
 	DrawStringAtPoint("[", drawX, drawY, c.fg, c.bg)
+	if c.indeterminate {
+		pos, w := c.bouncePos, c.bounceWidth()
+		if pos > fillWidth-w {
+			// The bar has shrunk since the block last moved
+			pos = 0
+		}
+		FillWithChar(c.fullChar, drawX+1+pos, drawY, drawX+pos+w, drawY+(fillHeight-1), c.fg, c.bg)
+		DrawStringAtPoint("]", drawX+c.width-1, drawY, c.fg, c.bg)
+		return
+	}
 	numFull := int(float64(fillWidth) * float64(c.progress) / float64(c.total))
 	FillWithChar(c.fullChar, drawX+1, drawY, drawX+1+numFull, drawY+(fillHeight-1), useFg, c.bg)
 	DrawStringAtPoint("]", drawX+c.width-1, drawY, c.fg, c.bg)
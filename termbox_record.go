@@ -0,0 +1,312 @@
+package termboxUtil
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// RecordedEvent is an event and how long after the one before it it came in
+type RecordedEvent struct {
+	Delay time.Duration
+	Event termbox.Event
+}
+
+// Recording is a list of events, in the order they came in
+type Recording []RecordedEvent
+
+// recordLine is how a RecordedEvent is written out, one JSON object per line
+type recordLine struct {
+	Delay  int64            `json:"delay"`
+	Type   string           `json:"type"`
+	Mod    termbox.Modifier `json:"mod,omitempty"`
+	Key    termbox.Key      `json:"key,omitempty"`
+	Ch     string           `json:"ch,omitempty"`
+	Width  int              `json:"width,omitempty"`
+	Height int              `json:"height,omitempty"`
+	X      int              `json:"x,omitempty"`
+	Y      int              `json:"y,omitempty"`
+}
+
+var eventTypeNames = map[termbox.EventType]string{
+	termbox.EventKey:       "key",
+	termbox.EventResize:    "resize",
+	termbox.EventMouse:     "mouse",
+	termbox.EventError:     "error",
+	termbox.EventInterrupt: "interrupt",
+}
+
+// MarshalJSON writes the event with its delay in milliseconds
+func (e RecordedEvent) MarshalJSON() ([]byte, error) {
+	l := recordLine{
+		Delay: e.Delay.Milliseconds(),
+		Type:  eventTypeNames[e.Event.Type],
+		Mod:   e.Event.Mod, Key: e.Event.Key,
+		Width: e.Event.Width, Height: e.Event.Height,
+		X: e.Event.MouseX, Y: e.Event.MouseY,
+	}
+	if e.Event.Ch != 0 {
+		l.Ch = string(e.Event.Ch)
+	}
+	return json.Marshal(l)
+}
+
+// UnmarshalJSON reads an event written by MarshalJSON
+func (e *RecordedEvent) UnmarshalJSON(data []byte) error {
+	var l recordLine
+	if err := json.Unmarshal(data, &l); err != nil {
+		return err
+	}
+	typ, ok := termbox.EventNone, false
+	for k, v := range eventTypeNames {
+		if v == l.Type {
+			typ, ok = k, true
+		}
+	}
+	if !ok {
+		return errors.New("Unknown event type: " + l.Type)
+	}
+	var ch rune
+	for _, r := range l.Ch {
+		ch = r
+		break
+	}
+	*e = RecordedEvent{
+		Delay: time.Duration(l.Delay) * time.Millisecond,
+		Event: termbox.Event{Type: typ, Mod: l.Mod, Key: l.Key, Ch: ch,
+			Width: l.Width, Height: l.Height, MouseX: l.X, MouseY: l.Y,
+		},
+	}
+	return nil
+}
+
+// Events returns just the events in the recording
+func (r Recording) Events() []termbox.Event {
+	ret := make([]termbox.Event, len(r))
+	for idx := range r {
+		ret[idx] = r[idx].Event
+	}
+	return ret
+}
+
+// Duration returns how long the recording takes to play at normal speed
+func (r Recording) Duration() time.Duration {
+	var ret time.Duration
+	for idx := range r {
+		ret += r[idx].Delay
+	}
+	return ret
+}
+
+// ReadRecording reads the events written by an EventRecorder from rd
+func ReadRecording(rd io.Reader) (Recording, error) {
+	var ret Recording
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e RecordedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		ret = append(ret, e)
+	}
+	return ret, scanner.Err()
+}
+
+// LoadRecording reads the events written by an EventRecorder to the file at path
+func LoadRecording(path string) (Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadRecording(f)
+}
+
+/* EventRecorder */
+
+// EventRecorder writes events, and how long apart they came in, as
+// lines of JSON that can be read back with ReadRecording
+type EventRecorder struct {
+	enc  *json.Encoder
+	last time.Time
+	now  func() time.Time
+}
+
+// CreateEventRecorder creates a recorder that writes to w
+func CreateEventRecorder(w io.Writer) *EventRecorder {
+	r := EventRecorder{enc: json.NewEncoder(w), now: time.Now}
+	return &r
+}
+
+// Record writes the event ev. Only key, mouse and resize events are
+// recorded, the others don't come from the user (or, for raw events,
+// can't be played back).
+func (r *EventRecorder) Record(ev termbox.Event) error {
+	switch ev.Type {
+	case termbox.EventKey, termbox.EventMouse, termbox.EventResize:
+	default:
+		return nil
+	}
+	now := r.now()
+	var delay time.Duration
+	if !r.last.IsZero() {
+		delay = now.Sub(r.last)
+	}
+	r.last = now
+	return r.enc.Encode(RecordedEvent{Delay: delay, Event: ev})
+}
+
+/* EventPlayer */
+
+// EventPlayer plays a recording back, waiting between the events
+// as long as they were apart when they were recorded
+type EventPlayer struct {
+	recording Recording
+
+	mu      sync.Mutex
+	speed   float64
+	stop    chan struct{}
+	playing bool
+}
+
+// CreateEventPlayer creates a player for the recording rec, at normal speed
+func CreateEventPlayer(rec Recording) *EventPlayer {
+	p := EventPlayer{recording: rec, speed: 1}
+	return &p
+}
+
+// GetSpeed returns how many times faster than normal the recording is played
+func (p *EventPlayer) GetSpeed() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.speed
+}
+
+// SetSpeed sets how many times faster than normal the recording is
+// played. 0 plays it without waiting at all. It can be changed while
+// the recording is playing.
+func (p *EventPlayer) SetSpeed(s float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.speed = s
+}
+
+// IsPlaying returns whether the recording is being played
+func (p *EventPlayer) IsPlaying() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.playing
+}
+
+// Play sends each event in the recording to send, at the right time,
+// until it gets to the end, send returns false or the player is stopped.
+// It returns whether the whole recording was played.
+func (p *EventPlayer) Play(send func(termbox.Event) bool) bool {
+	stop := p.start()
+	if stop == nil {
+		return false
+	}
+	return p.run(stop, send)
+}
+
+// start marks the player as playing and returns the channel that stops
+// it, or nil if it's already playing
+func (p *EventPlayer) start() chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.playing {
+		return nil
+	}
+	p.stop, p.playing = make(chan struct{}), true
+	return p.stop
+}
+
+// run plays the recording until the end or until stop is closed
+func (p *EventPlayer) run(stop chan struct{}, send func(termbox.Event) bool) bool {
+	defer func() {
+		p.mu.Lock()
+		if p.stop == stop {
+			p.playing = false
+		}
+		p.mu.Unlock()
+	}()
+	for _, e := range p.recording {
+		if speed := p.GetSpeed(); speed > 0 && e.Delay > 0 {
+			select {
+			case <-time.After(time.Duration(float64(e.Delay) / speed)):
+			case <-stop:
+				return false
+			}
+		}
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+		if !send(e.Event) {
+			return false
+		}
+	}
+	return true
+}
+
+// Stop stops the recording being played
+func (p *EventPlayer) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.playing {
+		close(p.stop)
+		p.playing = false
+	}
+}
+
+// GetRecorder returns the recorder the app writes its events to, or nil
+func (a *App) GetRecorder() *EventRecorder { return a.recorder }
+
+// SetRecorder has the app write every event it handles to r, nil stops
+// recording. Events played back with Replay aren't written again.
+// An error writing an event stops the app like any other error.
+func (a *App) SetRecorder(r *EventRecorder) { a.recorder = r }
+
+// Replay plays p back into the app, each event is handled on the
+// goroutine running the app as if it came from the terminal (and Run
+// returns any error handling it). Any recording already being played is
+// stopped, and so is this one when the app stops. It does nothing if p
+// is already playing.
+// Played faster than normal, clicks close enough together can turn
+// into double clicks.
+func (a *App) Replay(p *EventPlayer) {
+	stop := p.start()
+	if stop == nil {
+		return
+	}
+	a.stopReplay()
+	a.player = p
+	go p.run(stop, func(ev termbox.Event) bool {
+		a.QueueUpdate(func() {
+			a.replaying = true
+			defer func() { a.replaying = false }()
+			if err := a.HandleEvent(ev); err != nil && a.replayErr == nil {
+				a.replayErr = err
+			}
+		})
+		return true
+	})
+}
+
+// stopReplay stops the recording being played into the app, if there is one
+func (a *App) stopReplay() {
+	if a.player != nil {
+		a.player.Stop()
+		a.player = nil
+	}
+}
@@ -0,0 +1,76 @@
+package termboxUtil_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func TestRecordingRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	rec := termboxUtil.CreateEventRecorder(&buf)
+	evs := append(screentest.Runes("hé"), screentest.Key(termbox.KeyBackspace2),
+		screentest.Resize(30, 10), screentest.Click(4, 2))
+	for _, ev := range evs {
+		if err := rec.Record(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, ev := range []termbox.Event{{Type: termbox.EventRaw, N: 3}, {Type: termbox.EventInterrupt}, {Type: termbox.EventNone}} {
+		if err := rec.Record(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := termboxUtil.ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Events(), evs) {
+		t.Errorf("expected the events back as they were recorded\nwant %+v\ngot  %+v", evs, got.Events())
+	}
+
+	fld := termboxUtil.CreateInputField(0, 0, 10, 1, termbox.ColorWhite, termbox.ColorBlack)
+	s := screentest.CreateScreen(10, 1)
+	s.Replay(fld, got)
+	if fld.GetValue() != "h" {
+		t.Errorf("expected the replayed value to be \"h\", got %q", fld.GetValue())
+	}
+}
+
+func TestReadRecordingUnknownType(t *testing.T) {
+	_, err := termboxUtil.ReadRecording(strings.NewReader(`{"delay": 0, "type": "raw"}`))
+	if err == nil {
+		t.Error("expected raw events not to be read")
+	}
+}
+
+func TestEventPlayerSetSpeedWhilePlaying(t *testing.T) {
+	rec := make(termboxUtil.Recording, 20)
+	for idx := range rec {
+		rec[idx] = termboxUtil.RecordedEvent{Delay: time.Millisecond, Event: screentest.Rune('a')}
+	}
+	p := termboxUtil.CreateEventPlayer(rec)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 10; k++ {
+			p.SetSpeed(float64(k + 1))
+		}
+	}()
+	var n int
+	if !p.Play(func(termbox.Event) bool { n++; return true }) || n != len(rec) {
+		t.Errorf("expected all %d events to be played, got %d", len(rec), n)
+	}
+	wg.Wait()
+	if p.GetSpeed() != 10 {
+		t.Errorf("expected the speed to be 10, got %v", p.GetSpeed())
+	}
+}
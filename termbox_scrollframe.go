@@ -5,88 +5,27 @@ import "github.com/nsf/termbox-go"
 // ScrollFrame is a frame for holding other elements
 // It manages it's own x/y, tab index
 type ScrollFrame struct {
-	id                  string
-	x, y, width, height int
-	scrollX, scrollY    int
-	tabIdx              int
-	fg, bg              termbox.Attribute
-	activeFg, activeBg  termbox.Attribute
-	bordered            bool
-	controls            []termboxControl
-	active              bool
+	BaseControl
+	scrollX, scrollY int
+	tabIdx           int
+	theme            *Theme
+	controls         []Control
+	mouse            mouseRouter
 }
 
 // CreateScrollFrame creates Scrolling Frame at x, y that is w by h
 func CreateScrollFrame(x, y, w, h int, fg, bg termbox.Attribute) *ScrollFrame {
-	c := ScrollFrame{
-		x: x, y: y, width: w, height: h,
-		fg: fg, bg: bg, activeFg: fg, activeBg: bg,
-	}
+	c := ScrollFrame{BaseControl: CreateBaseControl(x, y, w, h, fg, bg)}
 	return &c
 }
 
-// GetID returns this control's ID
-func (c *ScrollFrame) GetID() string { return c.id }
-
-// SetID sets this control's ID
-func (c *ScrollFrame) SetID(newID string) {
-	c.id = newID
-}
-
-// GetX returns the x position of the scroll frame
-func (c *ScrollFrame) GetX() int { return c.x }
-
-// SetX sets the x position of the scroll frame
-func (c *ScrollFrame) SetX(x int) {
-	c.x = x
-}
-
-// GetY returns the y position of the scroll frame
-func (c *ScrollFrame) GetY() int { return c.y }
-
-// SetY sets the y position of the scroll frame
-func (c *ScrollFrame) SetY(y int) {
-	c.y = y
-}
-
-// GetWidth returns the current width of the scroll frame
-func (c *ScrollFrame) GetWidth() int { return c.width }
-
-// SetWidth sets the current width of the scroll frame
-func (c *ScrollFrame) SetWidth(w int) {
-	c.width = w
-}
-
-// GetHeight returns the current height of the scroll frame
-func (c *ScrollFrame) GetHeight() int { return c.height }
-
-// SetHeight sets the current height of the scroll frame
-func (c *ScrollFrame) SetHeight(h int) {
-	c.height = h
-}
-
-// GetFgColor returns the foreground color
-func (c *ScrollFrame) GetFgColor() termbox.Attribute { return c.fg }
-
-// SetFgColor sets the foreground color
-func (c *ScrollFrame) SetFgColor(fg termbox.Attribute) {
-	c.fg = fg
-}
-
-// GetBgColor returns the background color
-func (c *ScrollFrame) GetBgColor() termbox.Attribute { return c.bg }
-
-// SetBgColor sets the current background color
-func (c *ScrollFrame) SetBgColor(bg termbox.Attribute) {
-	c.bg = bg
-}
-
-// IsBordered returns true or false if this scroll frame has a border
-func (c *ScrollFrame) IsBordered() bool { return c.bordered }
-
-// SetBordered sets whether we render a border around the scroll frame
-func (c *ScrollFrame) SetBordered(b bool) {
-	c.bordered = b
+// SetActive sets whether the frame is active, the control at
+// the tab index is active along with it
+func (c *ScrollFrame) SetActive(a bool) {
+	c.BaseControl.SetActive(a)
+	for idx := range c.controls {
+		c.controls[idx].SetActive(a && idx == c.tabIdx)
+	}
 }
 
 // GetScrollX returns the x distance scrolled
@@ -102,6 +41,7 @@ func (c *ScrollFrame) GetScrollY() int {
 // ScrollDown scrolls the frame down
 func (c *ScrollFrame) ScrollDown() {
 	c.scrollY++
+	c.dirty = true
 }
 
 // ScrollUp scrolls the frame up
@@ -109,6 +49,7 @@ func (c *ScrollFrame) ScrollUp() {
 	if c.scrollY > 0 {
 		c.scrollY--
 	}
+	c.dirty = true
 }
 
 // ScrollLeft scrolls the frame left
@@ -116,26 +57,126 @@ func (c *ScrollFrame) ScrollLeft() {
 	if c.scrollX > 0 {
 		c.scrollX--
 	}
+	c.dirty = true
 }
 
 // ScrollRight scrolls the frame right
 func (c *ScrollFrame) ScrollRight() {
 	c.scrollX++
+	c.dirty = true
 }
 
-// AddControl adds a control to the frame
-func (c *ScrollFrame) AddControl(t termboxControl) {
+// AddControl adds a control to the frame, applying the frame's theme to it
+func (c *ScrollFrame) AddControl(t Control) {
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
 	c.controls = append(c.controls, t)
+	c.dirty = true
+}
+
+// GetControls returns a slice of all controls
+func (c *ScrollFrame) GetControls() []Control {
+	return c.controls
+}
+
+// IndexOf returns the index of the control t in the frame, or -1
+func (c *ScrollFrame) IndexOf(t Control) int { return indexOfControl(c.controls, t) }
+
+// InsertControl inserts a control into the frame at index idx,
+// applying the frame's theme to it
+func (c *ScrollFrame) InsertControl(idx int, t Control) {
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
+	c.controls, c.tabIdx = insertControlAt(c.controls, c.tabIdx, idx, t)
+	c.SetActive(c.active)
+	c.dirty = true
+}
+
+// RemoveControl removes the control t from the frame,
+// returning false if it isn't in the frame
+func (c *ScrollFrame) RemoveControl(t Control) bool {
+	idx := c.IndexOf(t)
+	if idx == -1 {
+		return false
+	}
+	c.dirty = true
+	c.controls, c.tabIdx = removeControlAt(c.controls, c.tabIdx, idx)
+	if c.mouse.capture == t {
+		c.mouse.capture = nil
+	}
+	t.SetActive(false)
+	c.SetActive(c.active)
+	return true
+}
+
+// ReplaceControl puts the control t in the frame in place of old,
+// returning false if old isn't in the frame
+func (c *ScrollFrame) ReplaceControl(old, t Control) bool {
+	idx := c.IndexOf(old)
+	if idx == -1 {
+		return false
+	}
+	c.dirty = true
+	if c.theme != nil {
+		ApplyTheme(t, c.theme)
+	}
+	c.controls[idx] = t
+	if c.mouse.capture == old {
+		c.mouse.capture = nil
+	}
+	old.SetActive(false)
+	c.SetActive(c.active)
+	return true
+}
+
+// GetActiveControl returns the control at the tab index
+func (c *ScrollFrame) GetActiveControl() Control {
+	if c.tabIdx < len(c.controls) {
+		return c.controls[c.tabIdx]
+	}
+	return nil
+}
+
+// SetActiveControl makes t the active control, returning false if t isn't in the frame
+func (c *ScrollFrame) SetActiveControl(t Control) bool {
+	for idx := range c.controls {
+		if c.controls[idx] == t {
+			c.tabIdx = idx
+			c.SetActive(c.active)
+			return true
+		}
+	}
+	return false
+}
+
+// SetTheme applies the theme t to the frame and everything in it.
+// Controls added to the frame later get the theme as well.
+func (c *ScrollFrame) SetTheme(t *Theme) { ApplyTheme(c, t) }
+
+// GetTheme returns the theme applied to the frame, or nil
+func (c *ScrollFrame) GetTheme() *Theme { return c.theme }
+
+// GetClipRect returns the area that controls in the frame can draw to.
+// This is the inside of the border if the frame is bordered.
+func (c *ScrollFrame) GetClipRect() (int, int, int, int) {
+	if c.bordered {
+		return c.x + 1, c.y + 1, c.width - 1, c.height - 1
+	}
+	return c.x, c.y, c.width + 1, c.height + 1
 }
 
 // DrawControl figures out the relative position of the control,
-// sets it, draws it, then resets it.
-func (c *ScrollFrame) DrawControl(t termboxControl) {
+// taking the scroll into account, sets it, draws it clipped to
+// the frame, then resets it.
+func (c *ScrollFrame) DrawControl(t Control) {
 	if c.IsVisible(t) {
 		ctlX, ctlY := t.GetX(), t.GetY()
-		t.SetX((c.GetX() + ctlX))
-		t.SetY((c.GetY() + ctlY))
-		t.Draw()
+		t.SetX((c.GetX() + ctlX - c.scrollX))
+		t.SetY((c.GetY() + ctlY - c.scrollY))
+		clipX, clipY, clipW, clipH := c.GetClipRect()
+		DrawClipped(t, clipX, clipY, clipW, clipH)
 		t.SetX(ctlX)
 		t.SetY(ctlY)
 	}
@@ -143,7 +184,7 @@ func (c *ScrollFrame) DrawControl(t termboxControl) {
 
 // IsVisible takes a Termbox Control and returns whether
 // that control would be visible in the frame
-func (c *ScrollFrame) IsVisible(t termboxControl) bool {
+func (c *ScrollFrame) IsVisible(t Control) bool {
 	// Check if any part of t should be visible
 	cX, cY := t.GetX(), t.GetY()
 	if cX+t.GetWidth() >= c.scrollX && cX <= c.scrollX+c.width {
@@ -152,15 +193,57 @@ func (c *ScrollFrame) IsVisible(t termboxControl) bool {
 	return false
 }
 
+// ApplyTheme sets the colors of the frame and everything in it from the theme t
+func (c *ScrollFrame) ApplyTheme(t *Theme) {
+	c.fg, c.bg = t.Base.Fg, t.Base.Bg
+	c.activeFg, c.activeBg = t.Active.Fg, t.Active.Bg
+	c.borderStyle = t.BorderStyle
+	c.theme = t
+	for _, v := range c.controls {
+		ApplyTheme(v, t)
+	}
+}
+
 // HandleEvent accepts the termbox event and returns whether it was consumed
 func (c *ScrollFrame) HandleEvent(event termbox.Event) bool {
-	return false
+	// Focus is handled for the frame and everything in it as a whole
+	return CreateFocusManager(c).HandleEvent(event)
+}
+
+// HandleMouse sends the mouse event on to the control under it, giving
+// that control focus if it was clicked. The wheel scrolls the frame
+// if the control under it doesn't use it.
+func (c *ScrollFrame) HandleMouse(ev MouseEvent) bool {
+	clipX, clipY, clipW, clipH := c.GetClipRect()
+	if ev.Action != MouseDrag && ev.Action != MouseRelease &&
+		!inRect(ev.X+c.x, ev.Y+c.y, clipX, clipY, clipW, clipH) {
+		return false
+	}
+	idx, ret := c.mouse.route(c.controls, ev, c.scrollX, c.scrollY)
+	if idx >= 0 && !c.controls[idx].IsTabSkipped() {
+		c.SetActiveControl(c.controls[idx])
+	}
+	if !ret {
+		switch ev.Action {
+		case MouseWheelUp:
+			c.ScrollUp()
+			return true
+		case MouseWheelDown:
+			c.ScrollDown()
+			return true
+		}
+	}
+	return ret
 }
 
-// DrawToStrings generates a slice of strings with what should
-// be drawn to the screen
+// DrawToStrings returns the lines of text that Draw puts on the screen
 func (c *ScrollFrame) DrawToStrings() []string {
-	return []string{}
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *ScrollFrame) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
 }
 
 // Draw outputs the Scoll Frame on the screen
@@ -171,7 +254,7 @@ func (c *ScrollFrame) Draw() {
 	startX := c.x
 	startY := c.y
 	if c.bordered {
-		DrawBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.fg, c.bg)
+		DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", c.fg, c.bg)
 		maxWidth--
 		maxHeight--
 		x++
@@ -0,0 +1,116 @@
+package termboxUtil
+
+import (
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// SpinnerLine is a spinner drawn with ASCII lines
+var SpinnerLine = []string{"|", "/", "-", "\\"}
+
+// SpinnerDots is a spinner drawn with braille dots
+var SpinnerDots = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner shows that something is going on by cycling through frames
+// while it's spinning. It's animated by an App's ticks (or TickControls).
+type Spinner struct {
+	BaseControl
+	frames   []string
+	frame    int
+	interval time.Duration
+	last     time.Time
+	spinning bool
+}
+
+// CreateSpinner creates a spinner at x, y drawn with SpinnerLine. It
+// starts out spinning.
+func CreateSpinner(x, y int, fg, bg termbox.Attribute) *Spinner {
+	c := Spinner{BaseControl: CreateBaseControl(x, y, 1, 1, fg, bg),
+		frames: SpinnerLine, interval: 100 * time.Millisecond, spinning: true,
+	}
+	c.tabSkip = true
+	return &c
+}
+
+// GetFrames returns the frames the spinner cycles through
+func (c *Spinner) GetFrames() []string { return c.frames }
+
+// SetFrames sets the frames the spinner cycles through, the spinner
+// is made as wide as the widest one
+func (c *Spinner) SetFrames(frames []string) {
+	c.frames = frames
+	c.frame = 0
+	c.width = 0
+	for _, f := range frames {
+		if w := TextWidth(f); w > c.width {
+			c.width = w
+		}
+	}
+	c.dirty = true
+}
+
+// GetInterval returns how long each frame is shown for
+func (c *Spinner) GetInterval() time.Duration { return c.interval }
+
+// SetInterval sets how long each frame is shown for
+func (c *Spinner) SetInterval(d time.Duration) { c.interval = d }
+
+// IsSpinning returns whether the spinner is moving
+func (c *Spinner) IsSpinning() bool { return c.spinning }
+
+// Start starts the spinner moving
+func (c *Spinner) Start() { c.spinning, c.dirty = true, true }
+
+// Stop stops the spinner on the frame it's on
+func (c *Spinner) Stop() {
+	c.spinning = false
+	c.last = time.Time{}
+	c.dirty = true
+}
+
+// IsAnimating returns whether the spinner is moving
+func (c *Spinner) IsAnimating() bool {
+	return c.spinning && len(c.frames) > 0 && c.interval > 0
+}
+
+// Tick moves the spinner on a frame for every interval that has passed
+func (c *Spinner) Tick(now time.Time) bool {
+	if !c.spinning || len(c.frames) == 0 || c.interval <= 0 {
+		return false
+	}
+	if c.last.IsZero() {
+		c.last = now
+		return false
+	}
+	steps := int(now.Sub(c.last) / c.interval)
+	if steps <= 0 {
+		return false
+	}
+	c.frame = (c.frame + steps) % len(c.frames)
+	c.last = c.last.Add(time.Duration(steps) * c.interval)
+	return true
+}
+
+// HandleEvent accepts the termbox event and returns whether it was consumed
+func (c *Spinner) HandleEvent(event termbox.Event) bool {
+	return false
+}
+
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *Spinner) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *Spinner) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
+// Draw outputs the spinner's current frame on the screen
+func (c *Spinner) Draw() {
+	if len(c.frames) == 0 {
+		return
+	}
+	DrawStringAtPoint(c.frames[c.frame%len(c.frames)], c.x, c.y, c.fg, c.bg)
+}
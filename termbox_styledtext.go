@@ -0,0 +1,216 @@
+package termboxUtil
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// TextFormat is how a string of text is interpreted when it's drawn
+type TextFormat int
+
+const (
+	// TextPlain draws the text exactly as it is
+	TextPlain TextFormat = iota
+	// TextMarkup draws the text with inline style tags, see ParseStyledText
+	TextMarkup
+	// TextANSI draws the text following any ANSI color escape sequences
+	// in it, see ParseANSIText
+	TextANSI
+)
+
+// StyledRun is a piece of text that is all drawn in the same colors
+type StyledRun struct {
+	Text   string
+	Fg, Bg termbox.Attribute
+}
+
+var colorNames = map[string]termbox.Attribute{
+	"default": termbox.ColorDefault,
+	"black":   termbox.ColorBlack,
+	"red":     termbox.ColorRed,
+	"green":   termbox.ColorGreen,
+	"yellow":  termbox.ColorYellow,
+	"blue":    termbox.ColorBlue,
+	"magenta": termbox.ColorMagenta,
+	"cyan":    termbox.ColorCyan,
+	"white":   termbox.ColorWhite,
+}
+
+// ParseColor returns the termbox color for a color name (black, red, green,
+// yellow, blue, magenta, cyan, white or default) or a 256 color number (0-255).
+// Numbers over 15 are only drawn as the right colors in termbox.Output256
+// (see App.SetOutputMode), in the normal output mode they come out as
+// other colors.
+func ParseColor(name string) (termbox.Attribute, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if c, ok := colorNames[name]; ok {
+		return c, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= 0 && n <= 255 {
+		return termbox.Attribute(n + 1), nil
+	}
+	return termbox.ColorDefault, errors.New("Unknown color: " + name)
+}
+
+// parseStyleTag parses the inside of a style tag ("fg:bg:flags")
+// on top of the current colors. Fields that are left empty don't change,
+// a '-' resets a field back to the base colors.
+func parseStyleTag(tag string, fg, bg, baseFg, baseBg termbox.Attribute) (termbox.Attribute, termbox.Attribute, bool) {
+	if tag == "-" {
+		return baseFg, baseBg, true
+	}
+	fields := strings.Split(tag, ":")
+	if len(fields) > 3 {
+		return fg, bg, false
+	}
+	const attrMask = termbox.AttrBold | termbox.AttrUnderline | termbox.AttrReverse
+	attrs := fg & attrMask
+	fg, bg = fg&^attrMask, bg&^attrMask
+	var err error
+	switch fields[0] {
+	case "":
+	case "-":
+		fg = baseFg &^ attrMask
+	default:
+		if fg, err = ParseColor(fields[0]); err != nil {
+			return fg, bg, false
+		}
+	}
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "":
+		case "-":
+			bg = baseBg &^ attrMask
+		default:
+			if bg, err = ParseColor(fields[1]); err != nil {
+				return fg, bg, false
+			}
+		}
+	}
+	if len(fields) > 2 {
+		switch fields[2] {
+		case "":
+		case "-":
+			attrs = baseFg & attrMask
+		default:
+			attrs = 0
+			for _, f := range fields[2] {
+				switch f {
+				case 'b':
+					attrs |= termbox.AttrBold
+				case 'u':
+					attrs |= termbox.AttrUnderline
+				case 'r':
+					attrs |= termbox.AttrReverse
+				default:
+					return fg, bg, false
+				}
+			}
+		}
+	}
+	return fg | attrs, bg, true
+}
+
+// ParseStyledText splits str into runs of styled text, starting with the
+// colors fg and bg. Style tags look like "[fg:bg:flags]" where fg and bg are
+// color names or numbers (see ParseColor) and flags are any of b (bold),
+// u (underline) and r (reverse). Empty fields are left as they are and a
+// '-' field goes back to fg/bg, so "[red::b]error[-] text" draws "error" in
+// bold red and " text" normally. "[[" is a literal '['. Anything in
+// brackets that isn't a valid tag is drawn as it is. Color numbers over
+// 15 need termbox.Output256, see ParseColor.
+func ParseStyledText(str string, fg, bg termbox.Attribute) []StyledRun {
+	var ret []StyledRun
+	var curr strings.Builder
+	useFg, useBg := fg, bg
+	flush := func() {
+		if curr.Len() > 0 {
+			ret = append(ret, StyledRun{Text: curr.String(), Fg: useFg, Bg: useBg})
+			curr.Reset()
+		}
+	}
+	for len(str) > 0 {
+		if strings.HasPrefix(str, "[[") {
+			curr.WriteByte('[')
+			str = str[2:]
+			continue
+		}
+		if str[0] == '[' {
+			if end := strings.IndexByte(str, ']'); end > 0 {
+				if newFg, newBg, ok := parseStyleTag(str[1:end], useFg, useBg, fg, bg); ok {
+					flush()
+					useFg, useBg = newFg, newBg
+					str = str[end+1:]
+					continue
+				}
+			}
+		}
+		curr.WriteByte(str[0])
+		str = str[1:]
+	}
+	flush()
+	return ret
+}
+
+// ParseFormattedText splits str into runs of styled text using the format f
+func ParseFormattedText(str string, f TextFormat, fg, bg termbox.Attribute) []StyledRun {
+	switch f {
+	case TextMarkup:
+		return ParseStyledText(str, fg, bg)
+	case TextANSI:
+		return ParseANSIText(str, fg, bg)
+	}
+	return []StyledRun{{Text: str, Fg: fg, Bg: bg}}
+}
+
+// StyledRunsText returns just the text of the runs
+func StyledRunsText(runs []StyledRun) string {
+	var ret strings.Builder
+	for _, r := range runs {
+		ret.WriteString(r.Text)
+	}
+	return ret.String()
+}
+
+// FormattedTextWidth returns the number of cells str takes up on the
+// screen once it's been parsed using the format f
+func FormattedTextWidth(str string, f TextFormat) int {
+	return TextWidth(StyledRunsText(ParseFormattedText(str, f, 0, 0)))
+}
+
+// TruncateStyledRuns cuts the runs down to at most width cells
+func TruncateStyledRuns(runs []StyledRun, width int) []StyledRun {
+	var ret []StyledRun
+	for _, r := range runs {
+		if width <= 0 {
+			break
+		}
+		r.Text = TruncateText(r.Text, width)
+		width -= TextWidth(r.Text)
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+// DrawStyledRunsAtPoint draws the runs one after another starting at x, y
+func DrawStyledRunsAtPoint(runs []StyledRun, x, y int) (int, int) {
+	for _, r := range runs {
+		x, y = DrawStringAtPoint(r.Text, x, y, r.Fg, r.Bg)
+	}
+	return x, y
+}
+
+// DrawStyledStringAtPoint draws the styled text str (see ParseStyledText)
+// at x, y starting with the colors fg and bg
+func DrawStyledStringAtPoint(str string, x, y int, fg, bg termbox.Attribute) (int, int) {
+	return DrawStyledRunsAtPoint(ParseStyledText(str, fg, bg), x, y)
+}
+
+// DrawFormattedStringAtPoint draws str using the format f at x, y
+// starting with the colors fg and bg
+func DrawFormattedStringAtPoint(str string, f TextFormat, x, y int, fg, bg termbox.Attribute) (int, int) {
+	return DrawStyledRunsAtPoint(ParseFormattedText(str, f, fg, bg), x, y)
+}
@@ -0,0 +1,81 @@
+package termboxUtil
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+)
+
+/* Text Measuring Helpers */
+// Anything that lays out text should measure it with these rather
+// than len(), so wide (CJK, emoji) characters take up two cells and
+// combining marks take up none.
+
+// RuneWidth returns the number of cells the rune r takes up on the screen
+func RuneWidth(r rune) int {
+	return runewidth.RuneWidth(r)
+}
+
+// TextWidth returns the number of cells the string str takes up on the screen
+func TextWidth(str string) int {
+	return runewidth.StringWidth(str)
+}
+
+// TruncateText cuts str down to at most width cells, keeping the start.
+// It never cuts through the middle of a rune.
+func TruncateText(str string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	var w int
+	for idx, r := range str {
+		rw := RuneWidth(r)
+		if w+rw > width {
+			return str[:idx]
+		}
+		w += rw
+	}
+	return str
+}
+
+// TruncateTextLeft cuts str down to at most width cells, keeping the end.
+// It never cuts through the middle of a rune.
+func TruncateTextLeft(str string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	rns := []rune(str)
+	var w int
+	for idx := len(rns) - 1; idx >= 0; idx-- {
+		rw := RuneWidth(rns[idx])
+		if w+rw > width {
+			return string(rns[idx+1:])
+		}
+		w += rw
+	}
+	return str
+}
+
+// SplitTextAtWidth splits str into the part that fits in width cells
+// and whatever is left over
+func SplitTextAtWidth(str string, width int) (string, string) {
+	head := TruncateText(str, width)
+	return head, str[len(head):]
+}
+
+// CellsToString returns the text in a row of cells. Empty cells are
+// returned as spaces and the cell covered by the right half of a wide
+// rune is skipped.
+func CellsToString(cells []termbox.Cell) string {
+	var ret []rune
+	for idx := 0; idx < len(cells); idx++ {
+		r := cells[idx].Ch
+		if r == 0 {
+			r = ' '
+		}
+		ret = append(ret, r)
+		if RuneWidth(r) == 2 {
+			idx++
+		}
+	}
+	return string(ret)
+}
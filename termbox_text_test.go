@@ -0,0 +1,61 @@
+package termboxUtil_test
+
+import (
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func TestTextWidthHelpers(t *testing.T) {
+	if w := termboxUtil.TextWidth("日本é́a"); w != 6 {
+		t.Errorf("expected wide runes to take 2 cells and combining marks none, got a width of %d", w)
+	}
+	if got := termboxUtil.TruncateText("日本語", 5); got != "日本" {
+		t.Errorf("expected truncating to 5 cells not to cut a rune in half, got %q", got)
+	}
+	if got := termboxUtil.TruncateTextLeft("日本語", 5); got != "本語" {
+		t.Errorf("expected truncating from the left to keep whole runes, got %q", got)
+	}
+	if got := termboxUtil.AlignText("日本", 8, termboxUtil.AlignRight); got != "    日本" {
+		t.Errorf("expected aligning to pad by display width, got %q", got)
+	}
+	if head, rest := termboxUtil.SplitTextAtWidth("日本語", 3); head != "日" || rest != "本語" {
+		t.Errorf("expected a split at 3 cells to be \"日\" and \"本語\", got %q and %q", head, rest)
+	}
+}
+
+func TestDrawWideRunes(t *testing.T) {
+	s := screentest.CreateScreen(8, 1)
+	defer termboxUtil.SetCanvas(termboxUtil.SetCanvas(s.GetCanvas()))
+	x, _ := termboxUtil.DrawStringAtPoint("日本a", 0, 0, termbox.ColorWhite, termbox.ColorBlack)
+	if x != 5 {
+		t.Errorf("expected drawing to move on 5 cells, got %d", x)
+	}
+	s.AssertRune(t, 2, 0, '本')
+	s.AssertRune(t, 4, 0, 'a')
+}
+
+func TestControlsLayOutWideText(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	s := screentest.CreateScreen(10, 1)
+	cb := termboxUtil.CreateCheckbox("", 0, 0, 8, 1, fg, bg)
+	cb.SetTitle("日本語のテスト")
+	s.Draw(cb)
+	// Regions have a rune per cell, so the right half of a wide rune is blank
+	s.AssertRegion(t, 0, 0, []string{"[ ]日 本   "})
+
+	s = screentest.CreateScreen(8, 1)
+	fld := termboxUtil.CreateInputField(0, 0, 6, 1, fg, bg)
+	fld.SetActive(true)
+	s.Send(fld, screentest.Runes("日本語x")...)
+	s.Draw(fld)
+	// The field scrolls by whole runes to keep the cursor showing
+	s.AssertRegion(t, 0, 0, []string{"本 語 x   "})
+	s.AssertCell(t, 5, 0, ' ', bg, fg)
+
+	s = screentest.CreateScreen(9, 5)
+	s.Draw(termboxUtil.CreateMenu("", []string{"日本", "é", "abc"}, 0, 0, 8, 4, fg, bg))
+	s.AssertRegion(t, 0, 1, []string{"║日 本    ▒", "║é      ║"})
+}
@@ -0,0 +1,118 @@
+package termboxUtil
+
+import "github.com/nsf/termbox-go"
+
+// Style is a foreground and background color pair
+type Style struct {
+	Fg, Bg termbox.Attribute
+}
+
+// Theme is a set of named styles that can be applied to a whole tree of controls
+type Theme struct {
+	Name string
+	// Base is normal text and backgrounds
+	Base Style
+	// Active is the control that currently has focus
+	Active Style
+	// Focused is the focus indicator inside of the active control (cursors, highlights)
+	Focused Style
+	// Selected is for selected items, like menu options
+	Selected Style
+	// Disabled is for disabled items
+	Disabled Style
+	// Border is the border of containers
+	Border Style
+	// Title is the title of modals
+	Title Style
+	// Error is for input that isn't valid (see InputField.SetValidator)
+	Error Style
+	// Help is for help text
+	Help Style
+	// BorderStyle is the style borders are drawn in
+	BorderStyle BorderStyle
+	overrides   map[string]*Theme
+}
+
+// CreateTheme creates a theme named name with every style worked out from fg and bg
+func CreateTheme(name string, fg, bg termbox.Attribute) *Theme {
+	t := Theme{
+		Name:     name,
+		Base:     Style{fg, bg},
+		Active:   Style{fg | termbox.AttrBold, bg},
+		Focused:  Style{bg, fg},
+		Selected: Style{bg, fg},
+		Disabled: Style{termbox.ColorBlack | termbox.AttrBold, bg},
+		Border:   Style{fg, bg},
+		Title:    Style{fg | termbox.AttrBold, bg},
+		Error:    Style{termbox.ColorRed | termbox.AttrBold, bg},
+		Help:     Style{fg, bg},
+	}
+	return &t
+}
+
+// DarkTheme is light text on a black background
+var DarkTheme = CreateTheme("dark", termbox.ColorWhite, termbox.ColorBlack)
+
+// LightTheme is dark text on a white background
+var LightTheme = CreateTheme("light", termbox.ColorBlack, termbox.ColorWhite)
+
+// GetThemeByName returns the built in theme with the name n
+func GetThemeByName(n string) (*Theme, bool) {
+	for _, t := range []*Theme{DarkTheme, LightTheme} {
+		if t.Name == n {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Copy returns a copy of the theme (including its overrides)
+// that can be changed without changing t
+func (t *Theme) Copy() *Theme {
+	ret := *t
+	ret.overrides = make(map[string]*Theme)
+	for k, v := range t.overrides {
+		ret.overrides[k] = v
+	}
+	return &ret
+}
+
+// SetOverride makes the control with the ID id (and anything inside of it)
+// use the theme o instead of t whenever t is applied
+func (t *Theme) SetOverride(id string, o *Theme) {
+	if t.overrides == nil {
+		t.overrides = make(map[string]*Theme)
+	}
+	t.overrides[id] = o
+}
+
+// RemoveOverride removes the override for the control with the ID id
+func (t *Theme) RemoveOverride(id string) { delete(t.overrides, id) }
+
+// GetOverride returns the override for the control with the ID id, or nil
+func (t *Theme) GetOverride(id string) *Theme { return t.overrides[id] }
+
+// themedControl is a control that knows how to apply a theme to itself
+type themedControl interface {
+	ApplyTheme(*Theme)
+}
+
+// ApplyTheme applies the theme t to the control c, using any override
+// t has for c's ID. Containers pass the theme on to their controls.
+func ApplyTheme(c Control, t *Theme) {
+	if t == nil {
+		return
+	}
+	if o := t.GetOverride(c.GetID()); o != nil {
+		t = o
+	}
+	if v, ok := c.(themedControl); ok {
+		v.ApplyTheme(t)
+		markDirty(c)
+		return
+	}
+	c.SetFgColor(t.Base.Fg)
+	c.SetBgColor(t.Base.Bg)
+	c.SetActiveFgColor(t.Active.Fg)
+	c.SetActiveBgColor(t.Active.Bg)
+}
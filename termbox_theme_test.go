@@ -0,0 +1,65 @@
+package termboxUtil_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+func TestInputFieldErrorStyle(t *testing.T) {
+	s := screentest.CreateScreen(10, 1)
+	fld := termboxUtil.CreateInputField(0, 0, 10, 1, termbox.ColorWhite, termbox.ColorBlack)
+	termboxUtil.ApplyTheme(fld, termboxUtil.DarkTheme)
+	fld.SetValidator(func(v string) error {
+		if v == "" {
+			return errors.New("required")
+		}
+		return nil
+	})
+	fld.SetValue("ok")
+	s.Draw(fld)
+	base := termboxUtil.DarkTheme.Base
+	s.AssertCell(t, 0, 0, 'o', base.Fg, base.Bg)
+
+	s.Step(fld, screentest.Key(termbox.KeyBackspace2), screentest.Key(termbox.KeyBackspace2), screentest.Rune('x'))
+	if !fld.IsValid() {
+		t.Errorf("expected %q to be valid", fld.GetValue())
+	}
+	s.Step(fld, screentest.Key(termbox.KeyBackspace2))
+	if fld.GetError() == nil {
+		t.Error("expected an empty value to be invalid")
+	}
+	fld.SetValue("bad")
+	fld.SetValidator(func(string) error { return errors.New("never valid") })
+	s.Draw(fld)
+	e := termboxUtil.DarkTheme.Error
+	s.AssertCell(t, 0, 0, 'b', e.Fg, e.Bg)
+}
+
+func TestUIColorsWinOverTheme(t *testing.T) {
+	ui, err := termboxUtil.LoadUI([]byte(`{"type": "frame", "width": 20, "height": 5, "theme": "dark",
+		"controls": [
+			{"type": "label", "id": "plain", "x": 1, "y": 1, "width": 5, "height": 1, "text": "a"},
+			{"type": "label", "id": "red", "x": 1, "y": 2, "width": 5, "height": 1, "text": "b", "fg": "red", "activeBg": "blue"}
+		]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := termboxUtil.FindByID(ui, "plain")
+	if plain.GetFgColor() != termboxUtil.DarkTheme.Base.Fg {
+		t.Errorf("expected the themed label to use the theme's fg, got %d", plain.GetFgColor())
+	}
+	red, _ := termboxUtil.FindByIDAs[*termboxUtil.Label](ui, "red")
+	if red.GetFgColor() != termbox.ColorRed {
+		t.Errorf("expected the label's own fg to win over the theme, got %d", red.GetFgColor())
+	}
+	if red.GetBgColor() != termboxUtil.DarkTheme.Base.Bg {
+		t.Errorf("expected the label's bg to come from the theme, got %d", red.GetBgColor())
+	}
+	if red.GetActiveBgColor() != termbox.ColorBlue {
+		t.Errorf("expected the label's own active bg to win over the theme, got %d", red.GetActiveBgColor())
+	}
+}
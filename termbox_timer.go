@@ -0,0 +1,239 @@
+package termboxUtil
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultFrameRate is how many times a second an App ticks its controls
+const DefaultFrameRate = 20
+
+// tickControl is a control that changes over time, like a Spinner.
+// Tick is called with the current time at the app's frame rate and
+// returns whether the control needs to be redrawn. Controls only tick
+// while they're in the app's tree, so there's nothing to cancel when
+// one is removed. IsAnimating returns whether the control needs ticking
+// at the moment, an App only wakes up at its frame rate while something
+// in its tree does.
+type tickControl interface {
+	Tick(now time.Time) bool
+	IsAnimating() bool
+}
+
+// isAnimating returns whether root or anything under it needs ticking
+func isAnimating(root Control) bool {
+	var ret bool
+	walkDamage(root, func(t Control) {
+		if v, ok := t.(tickControl); ok && !ret {
+			ret = v.IsAnimating()
+		}
+	})
+	return ret
+}
+
+// TickControls calls Tick on root and every control under it that
+// animates, and returns whether any of them need to be redrawn. Those
+// that do are marked dirty.
+// An App does this at its frame rate, anything running its own
+// event loop can call it from a time.Ticker.
+func TickControls(root Control, now time.Time) bool {
+	var ret bool
+	walkDamage(root, func(t Control) {
+		if v, ok := t.(tickControl); ok && v.Tick(now) {
+			markDirty(t)
+			ret = true
+		}
+	})
+	return ret
+}
+
+// Timer is a function an App runs after a delay, or over and over
+// (see App.After and App.Every)
+type Timer struct {
+	app      *App
+	owner    Control
+	f        func()
+	interval time.Duration
+	repeat   bool
+	stopped  int32
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// After runs f on the goroutine running the app once d has passed, and
+// redraws the screen afterwards. The returned Timer can stop it first.
+func (a *App) After(d time.Duration, f func()) *Timer {
+	return a.startTimer(d, f, false)
+}
+
+// Every runs f on the goroutine running the app each time d passes, and
+// redraws the screen afterwards, until the returned Timer is stopped.
+// It's only run while the app is running.
+func (a *App) Every(d time.Duration, f func()) *Timer {
+	return a.startTimer(d, f, true)
+}
+
+// AfterFor is After for a timer that belongs to the control t, it's
+// stopped once t is no longer under the app's root (removed from its
+// Frame, popped off a ModalStack...)
+func (a *App) AfterFor(t Control, d time.Duration, f func()) *Timer {
+	return a.startOwnedTimer(t, d, f, false)
+}
+
+// EveryFor is Every for a timer that belongs to the control t, it's
+// stopped once t is no longer under the app's root (removed from its
+// Frame, popped off a ModalStack...)
+func (a *App) EveryFor(t Control, d time.Duration, f func()) *Timer {
+	return a.startOwnedTimer(t, d, f, true)
+}
+
+func (a *App) startTimer(d time.Duration, f func(), repeat bool) *Timer {
+	t := Timer{app: a, f: f, interval: d, repeat: repeat}
+	t.mu.Lock()
+	t.timer = time.AfterFunc(d, t.fire)
+	t.mu.Unlock()
+	return &t
+}
+
+func (a *App) startOwnedTimer(owner Control, d time.Duration, f func(), repeat bool) *Timer {
+	t := a.startTimer(d, f, repeat)
+	t.owner = owner
+	a.timerMu.Lock()
+	a.ownedTimers = append(a.ownedTimers, t)
+	a.timerMu.Unlock()
+	return t
+}
+
+// stopOrphanedTimers stops the timers whose controls aren't under the
+// root anymore, and forgets the ones that have stopped. The event loop
+// does this after every event, so a timer's function never runs once
+// its control has gone.
+func (a *App) stopOrphanedTimers() {
+	a.timerMu.Lock()
+	defer a.timerMu.Unlock()
+	if len(a.ownedTimers) == 0 {
+		return
+	}
+	inTree := make(map[Control]bool)
+	walkDamage(a.root, func(t Control) { inTree[t] = true })
+	running := a.ownedTimers[:0]
+	for _, t := range a.ownedTimers {
+		if !inTree[t.owner] {
+			t.Stop()
+		}
+		if t.IsRunning() {
+			running = append(running, t)
+		}
+	}
+	for k := len(running); k < len(a.ownedTimers); k++ {
+		a.ownedTimers[k] = nil
+	}
+	a.ownedTimers = running
+}
+
+// fire queues the timer's function on the app. A repeating timer skips
+// the times it goes off while the app isn't running, rather than piling
+// updates up for when it is.
+func (t *Timer) fire() {
+	if !t.IsRunning() {
+		return
+	}
+	if !t.repeat || atomic.LoadInt32(&t.app.looping) == 1 {
+		t.app.QueueUpdate(func() {
+			if t.repeat && t.IsRunning() || atomic.CompareAndSwapInt32(&t.stopped, 0, 1) {
+				t.f()
+			}
+		})
+	}
+	if t.repeat {
+		t.mu.Lock()
+		t.timer.Reset(t.interval)
+		t.mu.Unlock()
+	}
+}
+
+// Stop stops the timer, its function won't be run again (even if it's
+// already been queued on the app). It returns false if it was already stopped.
+func (t *Timer) Stop() bool {
+	if !atomic.CompareAndSwapInt32(&t.stopped, 0, 1) {
+		return false
+	}
+	t.mu.Lock()
+	t.timer.Stop()
+	t.mu.Unlock()
+	return true
+}
+
+// IsRunning returns whether the timer's function will still be run
+func (t *Timer) IsRunning() bool { return atomic.LoadInt32(&t.stopped) == 0 }
+
+// GetOwner returns the control the timer belongs to, or nil
+func (t *Timer) GetOwner() Control { return t.owner }
+
+// GetFrameRate returns how many times a second the app ticks its controls
+func (a *App) GetFrameRate() int { return a.frameRate }
+
+// SetFrameRate sets how many times a second the app ticks its controls,
+// 0 turns animation off
+func (a *App) SetFrameRate(fps int) {
+	a.frameRate = fps
+	if a.running {
+		a.stopFrames()
+		a.updateFrames()
+	}
+}
+
+// Tick ticks every control under the root and, if any of them changed,
+// has the screen redrawn. The event loop does this at the frame rate.
+func (a *App) Tick(now time.Time) {
+	if a.root != nil && TickControls(a.root, now) {
+		a.redraw = true
+	}
+}
+
+// updateFrames starts the ticks if something under the root is
+// animating and stops them if nothing is, so an idle app isn't woken
+// up at the frame rate. The event loop does this after every event.
+func (a *App) updateFrames() {
+	if a.frameRate > 0 && a.root != nil && isAnimating(a.root) {
+		if a.frameStop == nil {
+			a.startFrames()
+		}
+	} else {
+		a.stopFrames()
+	}
+}
+
+// startFrames starts asking the event loop for a tick at the frame rate
+func (a *App) startFrames() {
+	if a.frameRate <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	a.frameStop = stop
+	ticker := time.NewTicker(time.Second / time.Duration(a.frameRate))
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				// Frames are dropped while the last one is waiting
+				if atomic.CompareAndSwapInt32(&a.tickRequested, 0, 1) {
+					a.wake()
+				}
+			}
+		}
+	}()
+}
+
+// stopFrames stops the ticks started by startFrames
+func (a *App) stopFrames() {
+	if a.frameStop != nil {
+		close(a.frameStop)
+		a.frameStop = nil
+	}
+}
@@ -0,0 +1,93 @@
+package termboxUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestTimersStopWithTheirControl(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	frm := CreateFrame(0, 0, 20, 10, fg, bg)
+	lbl := CreateLabel("x", 0, 0, 1, 1, fg, bg)
+	kept := CreateLabel("y", 0, 1, 1, 1, fg, bg)
+	frm.AddControl(lbl)
+	frm.AddControl(kept)
+	stack := CreateModalStack(frm, 0, 0, 20, 10)
+	modal := CreateAlertModal("", 0, 0, 10, 5, fg, bg)
+	stack.Push(modal)
+	a := CreateApp(stack)
+
+	lblTimer := a.EveryFor(lbl, time.Hour, func() {})
+	modalTimer := a.AfterFor(modal, time.Hour, func() {})
+	keptTimer := a.EveryFor(kept, time.Hour, func() {})
+	plain := a.After(time.Hour, func() {})
+	defer keptTimer.Stop()
+	defer plain.Stop()
+
+	a.stopOrphanedTimers()
+	if !lblTimer.IsRunning() || !modalTimer.IsRunning() {
+		t.Fatal("expected timers for controls in the tree to keep running")
+	}
+	frm.RemoveControl(lbl)
+	stack.Pop()
+	a.stopOrphanedTimers()
+	if lblTimer.IsRunning() {
+		t.Error("expected the timer to stop when its control was removed from the frame")
+	}
+	if modalTimer.IsRunning() {
+		t.Error("expected the timer to stop when its modal was popped")
+	}
+	if !keptTimer.IsRunning() || !plain.IsRunning() {
+		t.Error("expected the other timers to keep running")
+	}
+	if len(a.ownedTimers) != 1 || a.ownedTimers[0] != keptTimer {
+		t.Errorf("expected only the running timer to be kept, got %d", len(a.ownedTimers))
+	}
+}
+
+func TestFramesOnlyRunWhileAnimating(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	frm := CreateFrame(0, 0, 20, 10, fg, bg)
+	frm.AddControl(CreateInputField(0, 0, 10, 1, fg, bg))
+	a := CreateApp(frm)
+	defer a.stopFrames()
+	a.updateFrames()
+	if a.frameStop != nil {
+		t.Fatal("expected no frames while nothing is animating")
+	}
+	spin := CreateSpinner(0, 1, fg, bg)
+	frm.AddControl(spin)
+	a.updateFrames()
+	if a.frameStop == nil {
+		t.Fatal("expected frames while the spinner is spinning")
+	}
+	spin.Stop()
+	a.updateFrames()
+	if a.frameStop != nil {
+		t.Error("expected the frames to stop with the spinner")
+	}
+	a.SetFrameRate(0)
+	spin.Start()
+	a.updateFrames()
+	if a.frameStop != nil {
+		t.Error("expected no frames at a frame rate of 0")
+	}
+}
+
+func TestRepeatingTimerDoesntQueueWhileStopped(t *testing.T) {
+	a := CreateApp(nil)
+	tm := a.Every(time.Millisecond, func() {})
+	defer tm.Stop()
+	time.Sleep(20 * time.Millisecond)
+	a.updateMu.Lock()
+	n := len(a.updates)
+	a.updateMu.Unlock()
+	if n != 0 {
+		t.Errorf("expected nothing to be queued while the app isn't running, got %d updates", n)
+	}
+	if !tm.IsRunning() {
+		t.Error("expected the timer to still be running")
+	}
+}
@@ -0,0 +1,102 @@
+package termboxUtil
+
+import (
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Toast is a bordered message that goes away by itself after a while.
+// It's timed by an App's ticks (or TickControls), so it can be put in a
+// frame with a placement and shown whenever there's something to say:
+//
+//	toast := CreateToast(fg, bg)
+//	frame.AddPlacedControl(toast, PlaceAt(AnchorBottomRight, Auto(), Auto()))
+//	...
+//	toast.Show("Saved", 2*time.Second)
+type Toast struct {
+	BaseControl
+	text    string
+	timeout time.Duration
+	expires time.Time
+	visible bool
+}
+
+// CreateToast creates a toast that's hidden until it's shown
+func CreateToast(fg, bg termbox.Attribute) *Toast {
+	c := Toast{BaseControl: CreateBaseControl(0, 0, 0, 2, fg, bg)}
+	c.bordered = true
+	c.tabSkip = true
+	return &c
+}
+
+// Show shows text in the toast for d, the toast is made wide enough
+// for the text
+func (c *Toast) Show(text string, d time.Duration) {
+	c.text, c.timeout = text, d
+	c.expires = time.Time{}
+	c.width = TextWidth(text) + 3
+	c.visible = true
+	c.dirty = true
+}
+
+// Hide hides the toast before its time is up
+func (c *Toast) Hide() {
+	c.visible = false
+	c.dirty = true
+}
+
+// IsVisible returns whether the toast is showing
+func (c *Toast) IsVisible() bool { return c.visible }
+
+// GetText returns the text the toast was last shown with
+func (c *Toast) GetText() string { return c.text }
+
+// IsAnimating returns whether the toast is showing, waiting to go away
+func (c *Toast) IsAnimating() bool { return c.visible }
+
+// Tick hides the toast once its time is up. The time starts on the
+// first tick after it's shown.
+func (c *Toast) Tick(now time.Time) bool {
+	if !c.visible {
+		return false
+	}
+	if c.expires.IsZero() {
+		c.expires = now.Add(c.timeout)
+		return false
+	}
+	if now.Before(c.expires) {
+		return false
+	}
+	c.visible = false
+	return true
+}
+
+// HandleEvent accepts the termbox event and returns whether it was consumed
+func (c *Toast) HandleEvent(event termbox.Event) bool {
+	return false
+}
+
+// DrawToStrings returns the lines of text that Draw puts on the screen
+func (c *Toast) DrawToStrings() []string {
+	return DrawControlToBuffer(c).GetStrings()
+}
+
+// DrawToCells returns the cells (with colors) that Draw puts on the screen
+func (c *Toast) DrawToCells() [][]termbox.Cell {
+	return DrawControlToBuffer(c).GetCells()
+}
+
+// Draw outputs the toast on the screen if it's showing
+func (c *Toast) Draw() {
+	if !c.visible {
+		return
+	}
+	x, y := c.x, c.y
+	if c.bordered {
+		DrawStyledBorder(c.x, c.y, c.x+c.width, c.y+c.height, c.borderStyle, "", c.fg, c.bg)
+		x, y = x+1, y+1
+	}
+	FillWithChar(' ', x, y, c.x+c.width-1, c.y+c.height-1, c.fg, c.bg)
+	DrawStringAtPoint(c.text, x+1, y, c.fg, c.bg)
+}
@@ -0,0 +1,186 @@
+package termboxUtil
+
+// editableContainer is a container that controls can be
+// removed from and replaced in
+type editableContainer interface {
+	containerControl
+	RemoveControl(Control) bool
+	ReplaceControl(Control, Control) bool
+}
+
+// insertableContainer is a container that controls can be inserted into
+type insertableContainer interface {
+	editableContainer
+	InsertControl(int, Control)
+}
+
+// Walk calls fn for root and every control under it, depth first in the
+// order they were added. If fn returns false the controls under that
+// control are skipped.
+func Walk(root Control, fn func(t Control) bool) {
+	if root == nil || !fn(root) {
+		return
+	}
+	if v, ok := root.(containerControl); ok {
+		for _, t := range v.GetControls() {
+			Walk(t, fn)
+		}
+	}
+}
+
+// FindByID returns the first control under root (or root itself) with the ID id, or nil
+func FindByID(root Control, id string) Control {
+	var ret Control
+	if id == "" {
+		return nil
+	}
+	Walk(root, func(t Control) bool {
+		if ret == nil && t.GetID() == id {
+			ret = t
+		}
+		return ret == nil
+	})
+	return ret
+}
+
+// FindByIDAs returns the first control under root with the ID id as a T,
+// and false if there isn't one or it isn't a T
+//
+//	name, ok := FindByIDAs[*InputField](frame, "name")
+func FindByIDAs[T Control](root Control, id string) (T, bool) {
+	v, ok := FindByID(root, id).(T)
+	return v, ok
+}
+
+// FindAll returns every control under root (and root itself) that is a T
+func FindAll[T Control](root Control) []T {
+	var ret []T
+	Walk(root, func(t Control) bool {
+		if v, ok := t.(T); ok {
+			ret = append(ret, v)
+		}
+		return true
+	})
+	return ret
+}
+
+// FindParent returns the container under root that holds t, or nil
+func FindParent(root, t Control) Control {
+	path := findPath(root, t)
+	if len(path) < 2 {
+		return nil
+	}
+	return path[len(path)-2]
+}
+
+// findChild returns the control with the ID id, the container it's in,
+// and its index in that container
+func findChild(root Control, id string) (Control, containerControl, int) {
+	var parent containerControl
+	var idx int
+	var ret Control
+	if id == "" {
+		return nil, nil, 0
+	}
+	Walk(root, func(t Control) bool {
+		if ret != nil {
+			return false
+		}
+		v, ok := t.(containerControl)
+		if !ok {
+			return true
+		}
+		for k, c := range v.GetControls() {
+			if c.GetID() == id {
+				ret, parent, idx = c, v, k
+				return false
+			}
+		}
+		return true
+	})
+	return ret, parent, idx
+}
+
+// RemoveByID removes the control with the ID id from whatever container
+// under root it's in. It returns false if there isn't one or its container
+// doesn't allow controls to be removed.
+func RemoveByID(root Control, id string) bool {
+	t, parent, _ := findChild(root, id)
+	if v, ok := parent.(editableContainer); ok {
+		return v.RemoveControl(t)
+	}
+	return false
+}
+
+// ReplaceByID puts t in place of the control with the ID id, wherever it
+// is under root. It returns false if there isn't one or its container
+// doesn't allow controls to be replaced.
+func ReplaceByID(root Control, id string, t Control) bool {
+	old, parent, _ := findChild(root, id)
+	if v, ok := parent.(editableContainer); ok {
+		return v.ReplaceControl(old, t)
+	}
+	return false
+}
+
+// InsertBeforeID inserts t just before the control with the ID id, wherever
+// it is under root. It returns false if there isn't one or its container
+// doesn't allow controls to be inserted.
+func InsertBeforeID(root Control, id string, t Control) bool {
+	return insertByID(root, id, t, 0)
+}
+
+// InsertAfterID inserts t just after the control with the ID id, wherever
+// it is under root. It returns false if there isn't one or its container
+// doesn't allow controls to be inserted.
+func InsertAfterID(root Control, id string, t Control) bool {
+	return insertByID(root, id, t, 1)
+}
+
+func insertByID(root Control, id string, t Control, after int) bool {
+	_, parent, idx := findChild(root, id)
+	if v, ok := parent.(insertableContainer); ok {
+		v.InsertControl(idx+after, t)
+		return true
+	}
+	return false
+}
+
+// indexOfControl returns the index of t in ctls, or -1
+func indexOfControl(ctls []Control, t Control) int {
+	for idx := range ctls {
+		if ctls[idx] == t {
+			return idx
+		}
+	}
+	return -1
+}
+
+// insertControlAt inserts t into ctls at idx, returning the new slice
+// and the tab index moved so it's still on the same control
+func insertControlAt(ctls []Control, tabIdx, idx int, t Control) ([]Control, int) {
+	if idx < 0 {
+		idx = 0
+	}
+	if idx > len(ctls) {
+		idx = len(ctls)
+	}
+	ctls = append(ctls, nil)
+	copy(ctls[idx+1:], ctls[idx:])
+	ctls[idx] = t
+	if idx <= tabIdx && len(ctls) > 1 {
+		tabIdx++
+	}
+	return ctls, tabIdx
+}
+
+// removeControlAt removes the control at idx from ctls, returning the new
+// slice and the tab index moved so it's still on the same control, or
+// the one that took the removed control's place
+func removeControlAt(ctls []Control, tabIdx, idx int) ([]Control, int) {
+	ctls = append(ctls[:idx], ctls[idx+1:]...)
+	if idx < tabIdx || (tabIdx >= len(ctls) && tabIdx > 0) {
+		tabIdx--
+	}
+	return ctls, tabIdx
+}
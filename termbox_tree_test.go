@@ -0,0 +1,90 @@
+package termboxUtil_test
+
+import (
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+	"github.com/nsf/termbox-go"
+)
+
+// createTestTree builds a frame holding a scroll frame with two fields
+// and a nested frame with a button
+func createTestTree() *termboxUtil.Frame {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	withID := func(t termboxUtil.Control, id string) termboxUtil.Control {
+		t.(interface{ SetID(string) }).SetID(id)
+		return t
+	}
+	main := termboxUtil.CreateFrame(0, 0, 30, 10, fg, bg)
+	list := termboxUtil.CreateScrollFrame(1, 1, 20, 4, fg, bg)
+	list.AddControl(withID(termboxUtil.CreateInputField(0, 0, 10, 1, fg, bg), "a"))
+	list.AddControl(withID(termboxUtil.CreateInputField(0, 1, 10, 1, fg, bg), "b"))
+	inner := termboxUtil.CreateFrame(1, 6, 20, 2, fg, bg)
+	inner.AddControl(withID(termboxUtil.CreateButton(1, 1, 4, 1, fg, bg), "ok"))
+	main.AddControl(withID(list, "list"))
+	main.AddControl(withID(inner, "inner"))
+	return main
+}
+
+func TestFindByID(t *testing.T) {
+	main := createTestTree()
+	if _, ok := termboxUtil.FindByIDAs[*termboxUtil.Button](main, "ok"); !ok {
+		t.Error("expected to find the button in the nested frame")
+	}
+	if _, ok := termboxUtil.FindByIDAs[*termboxUtil.Button](main, "a"); ok {
+		t.Error("expected a field not to be found as a button")
+	}
+	if termboxUtil.FindByID(main, "nope") != nil {
+		t.Error("expected nothing to be found for an unknown ID")
+	}
+	if n := len(termboxUtil.FindAll[*termboxUtil.InputField](main)); n != 2 {
+		t.Errorf("expected to find 2 input fields, got %d", n)
+	}
+	if termboxUtil.FindParent(main, termboxUtil.FindByID(main, "b")) != termboxUtil.FindByID(main, "list") {
+		t.Error("expected b's parent to be the list")
+	}
+	var seen []string
+	termboxUtil.Walk(main, func(c termboxUtil.Control) bool {
+		seen = append(seen, c.GetID())
+		return c.GetID() != "list"
+	})
+	if len(seen) != 4 || seen[1] != "list" || seen[2] != "inner" || seen[3] != "ok" {
+		t.Errorf("expected the walk to skip the list's controls, got %q", seen)
+	}
+}
+
+func TestEditByID(t *testing.T) {
+	fg, bg := termbox.ColorWhite, termbox.ColorBlack
+	main := createTestTree()
+	m := termboxUtil.CreateFocusManager(main)
+	b := termboxUtil.FindByID(main, "b")
+	m.Focus(b)
+
+	lbl := termboxUtil.CreateLabel("new", 12, 0, 3, 1, fg, bg)
+	lbl.SetID("new")
+	if !termboxUtil.InsertBeforeID(main, "a", lbl) {
+		t.Fatal("expected to insert before a")
+	}
+	if m.GetFocused() != b {
+		t.Error("expected inserting a control not to move the focus")
+	}
+	list := termboxUtil.FindByID(main, "list").(*termboxUtil.ScrollFrame)
+	if list.IndexOf(lbl) != 0 {
+		t.Error("expected the label to be first in the list")
+	}
+	s := screentest.CreateScreen(31, 11)
+	s.Draw(main)
+	s.AssertText(t, 13, 1, "new")
+
+	if !termboxUtil.RemoveByID(main, "a") || termboxUtil.FindByID(main, "a") != nil {
+		t.Error("expected a to be removed")
+	}
+	btn := termboxUtil.CreateButton(1, 1, 6, 1, fg, bg)
+	if !termboxUtil.ReplaceByID(main, "ok", btn) || termboxUtil.FindByID(main, "inner").(*termboxUtil.Frame).GetControl(0) != btn {
+		t.Error("expected the ok button to be replaced")
+	}
+	if termboxUtil.RemoveByID(main, "nope") || termboxUtil.InsertAfterID(main, "nope", lbl) {
+		t.Error("expected edits by an unknown ID to fail")
+	}
+}
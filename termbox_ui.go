@@ -0,0 +1,497 @@
+package termboxUtil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// UISpec describes a control and everything in it, so a screen can be
+// loaded from a file (see LoadUI) instead of being built in code:
+//
+//	{"type": "frame", "id": "main", "width": 60, "height": 20, "theme": "dark",
+//	 "controls": [
+//	   {"type": "label", "x": 1, "y": 1, "width": 20, "height": 1, "text": "Name"},
+//	   {"type": "inputfield", "id": "name", "x": 1, "y": 2, "width": 20, "height": 1},
+//	   {"type": "confirmmodal", "title": "Save?", "height": 6,
+//	    "placement": {"anchor": "center", "width": "60%"}}
+//	 ]}
+//
+// Colors that aren't given are inherited from the parent. Only JSON is
+// loaded, the package doesn't depend on a YAML library. The fields have
+// yaml tags though, so a YAML document can be decoded into a UISpec with
+// whichever YAML package the program already uses and passed to BuildUI.
+type UISpec struct {
+	Type string `json:"type" yaml:"type"`
+	ID   string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	X      int `json:"x,omitempty" yaml:"x,omitempty"`
+	Y      int `json:"y,omitempty" yaml:"y,omitempty"`
+	Width  int `json:"width,omitempty" yaml:"width,omitempty"`
+	Height int `json:"height,omitempty" yaml:"height,omitempty"`
+
+	// Colors are names or 256 color numbers, see ParseColor (numbers
+	// over 15 need termbox.Output256)
+	Fg       string `json:"fg,omitempty" yaml:"fg,omitempty"`
+	Bg       string `json:"bg,omitempty" yaml:"bg,omitempty"`
+	ActiveFg string `json:"activeFg,omitempty" yaml:"activeFg,omitempty"`
+	ActiveBg string `json:"activeBg,omitempty" yaml:"activeBg,omitempty"`
+	// Theme is the name of a built in theme applied to this control and everything in it
+	Theme string `json:"theme,omitempty" yaml:"theme,omitempty"`
+
+	Bordered    *bool  `json:"bordered,omitempty" yaml:"bordered,omitempty"`
+	BorderStyle string `json:"borderStyle,omitempty" yaml:"borderStyle,omitempty"`
+	TabSkip     *bool  `json:"tabSkip,omitempty" yaml:"tabSkip,omitempty"`
+	TabOrder    int    `json:"tabOrder,omitempty" yaml:"tabOrder,omitempty"`
+	Keymap      string `json:"keymap,omitempty" yaml:"keymap,omitempty"`
+
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	Text  string `json:"text,omitempty" yaml:"text,omitempty"`
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Format is how text is interpreted: plain, markup or ansi
+	Format   string   `json:"format,omitempty" yaml:"format,omitempty"`
+	Options  []string `json:"options,omitempty" yaml:"options,omitempty"`
+	Lines    []string `json:"lines,omitempty" yaml:"lines,omitempty"`
+	Checked  bool     `json:"checked,omitempty" yaml:"checked,omitempty"`
+	Total    int      `json:"total,omitempty" yaml:"total,omitempty"`
+	Progress int      `json:"progress,omitempty" yaml:"progress,omitempty"`
+
+	// Size is the size in a vbox or hbox, see ParseSize
+	Size    string `json:"size,omitempty" yaml:"size,omitempty"`
+	MinSize int    `json:"minSize,omitempty" yaml:"minSize,omitempty"`
+	MaxSize int    `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+	// Row, Col, RowSpan and ColSpan are where the control goes in a grid
+	Row     int `json:"row,omitempty" yaml:"row,omitempty"`
+	Col     int `json:"col,omitempty" yaml:"col,omitempty"`
+	RowSpan int `json:"rowSpan,omitempty" yaml:"rowSpan,omitempty"`
+	ColSpan int `json:"colSpan,omitempty" yaml:"colSpan,omitempty"`
+	// Rows and Columns are the sizes of a grid's rows and columns
+	Rows    []string `json:"rows,omitempty" yaml:"rows,omitempty"`
+	Columns []string `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Padding int      `json:"padding,omitempty" yaml:"padding,omitempty"`
+	Spacing int      `json:"spacing,omitempty" yaml:"spacing,omitempty"`
+	// Placement places the control in a frame instead of X, Y, Width and Height
+	Placement *PlacementSpec `json:"placement,omitempty" yaml:"placement,omitempty"`
+
+	Controls []*UISpec `json:"controls,omitempty" yaml:"controls,omitempty"`
+	// Props holds anything else a custom control type needs
+	Props map[string]interface{} `json:"props,omitempty" yaml:"props,omitempty"`
+}
+
+// PlacementSpec describes a Placement
+type PlacementSpec struct {
+	// Anchor is center, top, bottom, left, right, top-left, top-right,
+	// bottom-left or bottom-right
+	Anchor  string `json:"anchor,omitempty" yaml:"anchor,omitempty"`
+	Width   string `json:"width,omitempty" yaml:"width,omitempty"`
+	Height  string `json:"height,omitempty" yaml:"height,omitempty"`
+	OffsetX int    `json:"offsetX,omitempty" yaml:"offsetX,omitempty"`
+	OffsetY int    `json:"offsetY,omitempty" yaml:"offsetY,omitempty"`
+}
+
+// ControlBuilder creates a control from a spec in the colors fg and bg.
+// The builder doesn't need to handle the fields every control has (id,
+// border, colors, title, text, keymap...) or add the spec's controls,
+// BuildUI does that afterwards.
+type ControlBuilder func(spec *UISpec, fg, bg termbox.Attribute) (Control, error)
+
+var controlBuilders = map[string]ControlBuilder{
+	"frame": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateFrame(s.X, s.Y, s.Width, s.Height, fg, bg), nil
+	},
+	"scrollframe": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateScrollFrame(s.X, s.Y, s.Width, s.Height, fg, bg), nil
+	},
+	"vbox": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		c := CreateVBox(s.X, s.Y, s.Width, s.Height, fg, bg)
+		c.SetPadding(UniformPadding(s.Padding))
+		c.SetSpacing(s.Spacing)
+		return c, nil
+	},
+	"hbox": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		c := CreateHBox(s.X, s.Y, s.Width, s.Height, fg, bg)
+		c.SetPadding(UniformPadding(s.Padding))
+		c.SetSpacing(s.Spacing)
+		return c, nil
+	},
+	"grid": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		rows, err := parseSizes(s.Rows)
+		if err != nil {
+			return nil, err
+		}
+		cols, err := parseSizes(s.Columns)
+		if err != nil {
+			return nil, err
+		}
+		c := CreateGrid(s.X, s.Y, s.Width, s.Height, rows, cols, fg, bg)
+		c.SetPadding(UniformPadding(s.Padding))
+		c.SetSpacing(s.Spacing)
+		return c, nil
+	},
+	"label": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateLabel(s.Text, s.X, s.Y, s.Width, s.Height, fg, bg), nil
+	},
+	"inputfield": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateInputField(s.X, s.Y, s.Width, s.Height, fg, bg), nil
+	},
+	"button": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		c := CreateButton(s.X, s.Y, s.Width, s.Height, fg, bg)
+		c.SetLabel(s.Text)
+		return c, nil
+	},
+	"checkbox": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		c := CreateCheckbox(s.Title, s.X, s.Y, s.Width, s.Height, fg, bg)
+		c.SetChecked(s.Checked)
+		return c, nil
+	},
+	"menu": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateMenu(s.Title, s.Options, s.X, s.Y, s.Width, s.Height, fg, bg), nil
+	},
+	"dropmenu": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateDropMenu(s.Title, s.Options, s.X, s.Y, s.Width, s.Height, fg, bg, bg, fg), nil
+	},
+	"progressbar": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		c := CreateProgressBar(s.Total, s.X, s.Y, fg, bg)
+		if s.Width > 0 {
+			c.SetWidth(s.Width)
+		}
+		if s.Height > 0 {
+			c.SetHeight(s.Height)
+		}
+		c.SetProgress(s.Progress)
+		return c, nil
+	},
+	"spinner": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateSpinner(s.X, s.Y, fg, bg), nil
+	},
+	"marquee": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateMarquee(s.Text, s.X, s.Y, s.Width, fg, bg), nil
+	},
+	"asciiart": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateASCIIArt(s.Lines, s.X, s.Y, fg, bg), nil
+	},
+	"alertmodal": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateAlertModal(s.Title, s.X, s.Y, s.Width, s.Height, fg, bg), nil
+	},
+	"confirmmodal": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateConfirmModal(s.Title, s.X, s.Y, s.Width, s.Height, fg, bg), nil
+	},
+	"inputmodal": func(s *UISpec, fg, bg termbox.Attribute) (Control, error) {
+		return CreateInputModal(s.Title, s.X, s.Y, s.Width, s.Height, fg, bg), nil
+	},
+}
+
+// RegisterControlType lets specs with the type name be built by b. This
+// can add custom controls or replace how the built in ones are made.
+// If the control has an AddControl(Control) method it can hold other controls.
+func RegisterControlType(name string, b ControlBuilder) {
+	controlBuilders[strings.ToLower(name)] = b
+}
+
+// LoadUI builds the control tree described by the JSON document data
+func LoadUI(data []byte) (Control, error) {
+	var spec UISpec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, err
+	}
+	return BuildUI(&spec)
+}
+
+// LoadUIFile builds the control tree described by the JSON file at path
+func LoadUIFile(path string) (Control, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadUI(data)
+}
+
+// BuildUI builds the control tree described by spec. Themes are applied
+// once everything is built, before the colors given for each control,
+// so those always win over the theme.
+func BuildUI(spec *UISpec) (Control, error) {
+	built := make(map[*UISpec]Control)
+	t, err := buildControl(spec, termbox.ColorDefault, termbox.ColorDefault, built)
+	if err != nil {
+		return nil, err
+	}
+	applySpecThemes(spec, built)
+	if err = applySpecColors(spec, built); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// uiError adds which control in the spec went wrong to err
+func uiError(spec *UISpec, err error) error {
+	if spec.ID != "" {
+		return fmt.Errorf("%s %q: %v", spec.Type, spec.ID, err)
+	}
+	return fmt.Errorf("%s: %v", spec.Type, err)
+}
+
+// buildControl builds the control described by spec and everything in
+// it, remembering which control each spec built in built
+func buildControl(spec *UISpec, fg, bg termbox.Attribute, built map[*UISpec]Control) (Control, error) {
+	b, ok := controlBuilders[strings.ToLower(spec.Type)]
+	if !ok {
+		return nil, uiError(spec, errors.New("Unknown control type"))
+	}
+	var err error
+	if fg, err = parseSpecColor(spec.Fg, fg); err != nil {
+		return nil, uiError(spec, err)
+	}
+	if bg, err = parseSpecColor(spec.Bg, bg); err != nil {
+		return nil, uiError(spec, err)
+	}
+	t, err := b(spec, fg, bg)
+	if err != nil {
+		return nil, uiError(spec, err)
+	}
+	if err = applySpec(t, spec); err != nil {
+		return nil, uiError(spec, err)
+	}
+	for _, cs := range spec.Controls {
+		child, err := buildControl(cs, fg, bg, built)
+		if err != nil {
+			return nil, uiError(spec, err)
+		}
+		if err = addSpecChild(t, child, cs); err != nil {
+			return nil, uiError(cs, err)
+		}
+	}
+	if _, ok := GetThemeByName(spec.Theme); spec.Theme != "" && !ok {
+		return nil, uiError(spec, errors.New("Unknown theme: "+spec.Theme))
+	}
+	built[spec] = t
+	return t, nil
+}
+
+// applySpecThemes applies the themes in spec to the controls they were
+// given for, outside in so a theme inside another one wins
+func applySpecThemes(spec *UISpec, built map[*UISpec]Control) {
+	if th, ok := GetThemeByName(spec.Theme); ok {
+		ApplyTheme(built[spec], th)
+	}
+	for _, cs := range spec.Controls {
+		applySpecThemes(cs, built)
+	}
+}
+
+// applySpecColors sets the colors given in spec on the controls they were
+// given for, over anything a theme set
+func applySpecColors(spec *UISpec, built map[*UISpec]Control) error {
+	t := built[spec]
+	for _, c := range []struct {
+		name string
+		set  func(termbox.Attribute)
+	}{
+		{spec.Fg, t.SetFgColor}, {spec.Bg, t.SetBgColor},
+		{spec.ActiveFg, t.SetActiveFgColor}, {spec.ActiveBg, t.SetActiveBgColor},
+	} {
+		if c.name == "" {
+			continue
+		}
+		col, err := ParseColor(c.name)
+		if err != nil {
+			return uiError(spec, err)
+		}
+		c.set(col)
+	}
+	for _, cs := range spec.Controls {
+		if err := applySpecColors(cs, built); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySpec sets everything in spec that isn't specific to one type of control
+func applySpec(t Control, spec *UISpec) error {
+	if v, ok := t.(interface{ SetID(string) }); ok && spec.ID != "" {
+		v.SetID(spec.ID)
+	}
+	if spec.Bordered != nil {
+		t.SetBordered(*spec.Bordered)
+	}
+	if spec.TabSkip != nil {
+		t.SetTabSkip(*spec.TabSkip)
+	}
+	if spec.BorderStyle != "" {
+		s, ok := GetBorderStyleByName(spec.BorderStyle)
+		if !ok {
+			return errors.New("Unknown border style: " + spec.BorderStyle)
+		}
+		if v, ok := t.(interface{ SetBorderStyle(BorderStyle) }); ok {
+			v.SetBorderStyle(s)
+		}
+	}
+	if spec.Keymap != "" {
+		k, ok := GetKeymapByName(spec.Keymap)
+		if !ok {
+			return errors.New("Unknown keymap: " + spec.Keymap)
+		}
+		if v, ok := t.(interface{ SetKeymap(*Keymap) }); ok {
+			v.SetKeymap(k)
+		}
+	}
+	if spec.Format != "" {
+		f, err := parseTextFormat(spec.Format)
+		if err != nil {
+			return err
+		}
+		if v, ok := t.(interface{ SetTextFormat(TextFormat) }); ok {
+			v.SetTextFormat(f)
+		}
+	}
+	if v, ok := t.(interface{ SetTitle(string) }); ok && spec.Title != "" {
+		v.SetTitle(spec.Title)
+	}
+	if v, ok := t.(interface{ SetText(string) }); ok && spec.Text != "" {
+		v.SetText(spec.Text)
+	}
+	if v, ok := t.(interface{ SetValue(string) }); ok && spec.Value != "" {
+		v.SetValue(spec.Value)
+	}
+	return nil
+}
+
+// addSpecChild adds child, described by spec, to the container t
+func addSpecChild(t, child Control, spec *UISpec) error {
+	switch v := t.(type) {
+	case *Box:
+		s, err := ParseSize(spec.Size)
+		if err != nil {
+			return err
+		}
+		s.Min, s.Max = spec.MinSize, spec.MaxSize
+		v.AddControl(child, s)
+	case *Grid:
+		rowSpan, colSpan := spec.RowSpan, spec.ColSpan
+		if rowSpan < 1 {
+			rowSpan = 1
+		}
+		if colSpan < 1 {
+			colSpan = 1
+		}
+		v.AddControlSpan(child, spec.Row, spec.Col, rowSpan, colSpan)
+	case *Frame:
+		v.AddControl(child)
+		if spec.Placement != nil {
+			p, err := spec.Placement.toPlacement()
+			if err != nil {
+				return err
+			}
+			v.SetPlacement(child, p)
+		}
+		if spec.TabOrder > 0 {
+			v.SetTabOrder(child, spec.TabOrder)
+		}
+	case interface{ AddControl(Control) }:
+		v.AddControl(child)
+	default:
+		return errors.New("Parent can't hold controls")
+	}
+	return nil
+}
+
+// parseSpecColor parses the color s, or returns def if it's empty
+func parseSpecColor(s string, def termbox.Attribute) (termbox.Attribute, error) {
+	if s == "" {
+		return def, nil
+	}
+	return ParseColor(s)
+}
+
+func parseTextFormat(s string) (TextFormat, error) {
+	switch strings.ToLower(s) {
+	case "plain":
+		return TextPlain, nil
+	case "markup":
+		return TextMarkup, nil
+	case "ansi":
+		return TextANSI, nil
+	}
+	return TextPlain, errors.New("Unknown text format: " + s)
+}
+
+// ParseSize parses a size: a number of cells ("10"), a percentage ("50%"),
+// a flex weight ("flex" or "flex:2") or "auto" (the default when s is empty)
+func ParseSize(s string) (Size, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case s == "" || s == "auto":
+		return Auto(), nil
+	case s == "flex":
+		return Flex(1), nil
+	case strings.HasPrefix(s, "flex:"):
+		n, err := strconv.Atoi(s[5:])
+		if err != nil || n < 1 {
+			return Size{}, errors.New("Bad flex weight: " + s)
+		}
+		return Flex(n), nil
+	case strings.HasSuffix(s, "%"):
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return Size{}, errors.New("Bad percentage: " + s)
+		}
+		return Percent(n), nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return Size{}, errors.New("Bad size: " + s)
+	}
+	return Fixed(n), nil
+}
+
+func parseSizes(strs []string) ([]Size, error) {
+	var ret []Size
+	for _, s := range strs {
+		sz, err := ParseSize(s)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, sz)
+	}
+	return ret, nil
+}
+
+var anchorNames = map[string]Anchor{
+	"top-left": AnchorTopLeft, "top": AnchorTop, "top-right": AnchorTopRight,
+	"left": AnchorLeft, "center": AnchorCenter, "right": AnchorRight,
+	"bottom-left": AnchorBottomLeft, "bottom": AnchorBottom, "bottom-right": AnchorBottomRight,
+}
+
+// ParseAnchor parses an anchor name like "center" or "bottom-right"
+func ParseAnchor(s string) (Anchor, error) {
+	if a, ok := anchorNames[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return a, nil
+	}
+	return AnchorTopLeft, errors.New("Unknown anchor: " + s)
+}
+
+func (p *PlacementSpec) toPlacement() (Placement, error) {
+	var ret Placement
+	var err error
+	if p.Anchor != "" {
+		if ret.Anchor, err = ParseAnchor(p.Anchor); err != nil {
+			return ret, err
+		}
+	}
+	if ret.Width, err = ParseSize(p.Width); err != nil {
+		return ret, err
+	}
+	if ret.Height, err = ParseSize(p.Height); err != nil {
+		return ret, err
+	}
+	ret.OffsetX, ret.OffsetY = p.OffsetX, p.OffsetY
+	return ret, nil
+}
@@ -0,0 +1,46 @@
+package termboxUtil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/br0xen/termbox-util"
+	"github.com/br0xen/termbox-util/screentest"
+)
+
+func TestLoadUIFile(t *testing.T) {
+	ui, err := termboxUtil.LoadUIFile("testdata/login.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, ok := termboxUtil.FindByIDAs[*termboxUtil.InputField](ui, "name")
+	if !ok {
+		t.Fatal("expected to find the name input field")
+	}
+	if name.GetValue() != "bob" {
+		t.Errorf("expected the name to be \"bob\", got %q", name.GetValue())
+	}
+	if _, ok = termboxUtil.FindByIDAs[*termboxUtil.Button](ui, "ok"); !ok {
+		t.Error("expected to find the ok button")
+	}
+	s := screentest.CreateScreen(21, 7)
+	s.Draw(ui)
+	s.AssertText(t, 1, 1, "Name")
+	s.AssertText(t, 1, 2, "bob")
+}
+
+func TestLoadUIErrors(t *testing.T) {
+	for _, tc := range []struct {
+		doc, want string
+	}{
+		{`{"type": "nope"}`, "nope"},
+		{`{"type": "label", "colour": "red"}`, "colour"},
+		{`{"type": "label", "fg": "mauve"}`, "mauve"},
+		{`type: label`, "invalid character"},
+	} {
+		_, err := termboxUtil.LoadUI([]byte(tc.doc))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("LoadUI(%s): expected an error about %q, got %v", tc.doc, tc.want, err)
+		}
+	}
+}
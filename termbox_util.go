@@ -4,11 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/nsf/termbox-go"
 )
 
-type termboxControl interface {
+// Control is anything that can be put in a Frame or any of the other
+// containers. Embed BaseControl in a struct to get everything but
+// HandleEvent and Draw.
+type Control interface {
 	GetID() string
 	GetX() int
 	SetX(int)
@@ -48,6 +52,13 @@ const (
 
 /* Basic Input Helpers */
 
+// KeyBackTab is Shift-Tab. Termbox doesn't have a key for it, so App
+// picks the escape sequence for it out of the terminal input itself.
+// That only happens under an App on unix: on windows termbox sends
+// Shift-Tab as a plain KeyTab, and anything running its own event loop
+// with termbox.PollEvent never gets KeyBackTab.
+const KeyBackTab = termbox.Key(0xFFFF - 64)
+
 // KeyIsAlphaNumeric Returns whether the termbox event is an
 // Alpha-Numeric Key Press
 func KeyIsAlphaNumeric(event termbox.Event) bool {
@@ -89,14 +100,31 @@ func KeyIsSymbol(event termbox.Event) bool {
 	return false
 }
 
+// KeyIsUnicodeText Returns whether the termbox event is a
+// printable, non-ASCII character (accented letters, CJK, emoji...)
+func KeyIsUnicodeText(event termbox.Event) bool {
+	return event.Ch > unicode.MaxASCII && unicode.IsPrint(event.Ch)
+}
+
 /* Basic Output Helpers */
 
+// SetCell sets the cell at x, y on the current canvas
+func SetCell(x, y int, r rune, fg, bg termbox.Attribute) {
+	currentCanvas.SetCell(x, y, r, fg, bg)
+}
+
 // DrawStringAtPoint Draw a string of text at x, y with foreground color fg, background color bg
+// Wide runes take up two cells, zero width runes (combining marks) are skipped
+// since a cell can only hold one rune.
 func DrawStringAtPoint(str string, x int, y int, fg termbox.Attribute, bg termbox.Attribute) (int, int) {
 	xPos := x
 	for _, runeValue := range str {
-		termbox.SetCell(xPos, y, runeValue, fg, bg)
-		xPos++
+		w := RuneWidth(runeValue)
+		if w == 0 {
+			continue
+		}
+		SetCell(xPos, y, runeValue, fg, bg)
+		xPos += w
 	}
 	return xPos, y
 }
@@ -105,73 +133,27 @@ func DrawStringAtPoint(str string, x int, y int, fg termbox.Attribute, bg termbo
 func FillWithChar(r rune, x1, y1, x2, y2 int, fg termbox.Attribute, bg termbox.Attribute) {
 	for xx := x1; xx <= x2; xx++ {
 		for yx := y1; yx <= y2; yx++ {
-			termbox.SetCell(xx, yx, r, fg, bg)
+			SetCell(xx, yx, r, fg, bg)
 		}
 	}
 }
 
 // DrawBorder Draw a border around the area inside x1,y1 -> x2, y2
+// using the DefaultBorderStyle
 func DrawBorder(x1, y1, x2, y2 int, fg, bg termbox.Attribute) {
-	termbox.SetCell(x1, y1, '╔', fg, bg)
-	FillWithChar('═', x1+1, y1, x2-1, y1, fg, bg)
-	termbox.SetCell(x2, y1, '╗', fg, bg)
-
-	FillWithChar('║', x1, y1+1, x1, y2-1, fg, bg)
-	FillWithChar('║', x2, y1+1, x2, y2-1, fg, bg)
-
-	termbox.SetCell(x1, y2, '╚', fg, bg)
-	FillWithChar('═', x1+1, y2, x2-1, y2, fg, bg)
-	termbox.SetCell(x2, y2, '╝', fg, bg)
+	DrawStyledBorder(x1, y1, x2, y2, DefaultBorderStyle, "", fg, bg)
 }
 
 func DrawBorderWithPct(x1, y1, x2, y2 int, pct float64, fg, bg termbox.Attribute) {
-	termbox.SetCell(x1, y1, '╔', fg, bg)
-
-	FillWithChar('═', x1+1, y1, x2-1, y1, fg, bg)
-	termbox.SetCell(x2, y1, '╗', fg, bg)
-
-	FillWithChar('║', x1, y1+1, x1, y2-1, fg, bg)
-	FillWithChar('║', x2, y1+1, x2, y2-1, fg, bg)
-	// Now the percent indicator
-	pctY := int(((float64(y2)-float64(y1)-2)*pct)+float64(y1)) + 1
-	termbox.SetCell(x2, pctY, '▒', fg, bg)
-
-	termbox.SetCell(x1, y2, '╚', fg, bg)
-	FillWithChar('═', x1+1, y2, x2-1, y2, fg, bg)
-	termbox.SetCell(x2, y2, '╝', fg, bg)
+	DrawStyledBorderWithPct(x1, y1, x2, y2, DefaultBorderStyle, "", pct, fg, bg)
 }
 
 func DrawBorderWithTitle(x1, y1, x2, y2 int, title string, fg, bg termbox.Attribute) {
-	termbox.SetCell(x1, y1, '╔', fg, bg)
-
-	DrawStringAtPoint(title, x1+1, y1, fg, bg)
-	FillWithChar('═', x1+len(title)+1, y1, x2-1, y1, fg, bg)
-	termbox.SetCell(x2, y1, '╗', fg, bg)
-
-	FillWithChar('║', x1, y1+1, x1, y2-1, fg, bg)
-	FillWithChar('║', x2, y1+1, x2, y2-1, fg, bg)
-
-	termbox.SetCell(x1, y2, '╚', fg, bg)
-	FillWithChar('═', x1+1, y2, x2-1, y2, fg, bg)
-	termbox.SetCell(x2, y2, '╝', fg, bg)
+	DrawStyledBorder(x1, y1, x2, y2, DefaultBorderStyle, title, fg, bg)
 }
 
 func DrawBorderWithTitleAndPct(x1, y1, x2, y2 int, title string, pct float64, fg, bg termbox.Attribute) {
-	termbox.SetCell(x1, y1, '╔', fg, bg)
-
-	DrawStringAtPoint(title, x1+1, y1, fg, bg)
-	FillWithChar('═', x1+len(title)+1, y1, x2-1, y1, fg, bg)
-	termbox.SetCell(x2, y1, '╗', fg, bg)
-
-	FillWithChar('║', x1, y1+1, x1, y2-1, fg, bg)
-	FillWithChar('║', x2, y1+1, x2, y2-1, fg, bg)
-	// Now the percent indicator
-	pctY := int(((float64(y2)-float64(y1)-2)*pct)+float64(y1)) + 1
-	termbox.SetCell(x2, pctY, '▒', fg, bg)
-
-	termbox.SetCell(x1, y2, '╚', fg, bg)
-	FillWithChar('═', x1+1, y2, x2-1, y2, fg, bg)
-	termbox.SetCell(x2, y2, '╝', fg, bg)
+	DrawStyledBorderWithPct(x1, y1, x2, y2, DefaultBorderStyle, title, pct, fg, bg)
 }
 
 // AlignText Aligns the text txt within width characters using the specified alignment
@@ -183,7 +165,7 @@ func AlignText(txt string, width int, align TextAlignment) string {
 // filling any spaces with the 'fill' character
 func AlignTextWithFill(txt string, width int, align TextAlignment, fill rune) string {
 	fillChar := string(fill)
-	numSpaces := width - len(txt)
+	numSpaces := width - TextWidth(txt)
 	switch align {
 	case AlignCenter:
 		if numSpaces/2 > 0 {
@@ -203,14 +185,14 @@ func AlignTextWithFill(txt string, width int, align TextAlignment, fill rune) st
 	}
 }
 
-func ToLabel(c termboxControl) (*Label, error) {
+func ToLabel(c Control) (*Label, error) {
 	v, ok := c.(*Label)
 	if ok {
 		return v, nil
 	}
 	return nil, errors.New("Control isn't a Label")
 }
-func ToInputField(c termboxControl) (*InputField, error) {
+func ToInputField(c Control) (*InputField, error) {
 	v, ok := c.(*InputField)
 	if ok {
 		return v, nil